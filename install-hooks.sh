@@ -1,25 +1,39 @@
 #!/bin/bash
 # Install Claude Code hooks for waiting indicator
-# This script copies hook scripts and merges hook configuration
+# This script copies the lunar-editor binary and merges hook configuration
 
 set -e
 
 SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
 CLAUDE_DIR="$HOME/.claude"
-HOOKS_DIR="$CLAUDE_DIR/hooks"
 SETTINGS_FILE="$CLAUDE_DIR/settings.json"
 
 echo "Installing Claude Code statusline hooks..."
 
-# Create hooks directory
-mkdir -p "$HOOKS_DIR"
+# Pick the lunar-editor binary for this platform; it handles the hook events
+case "$(uname -s)" in
+    Darwin) CANDIDATES="lunar-editor-macos lunar-editor" ;;
+    Linux)  CANDIDATES="lunar-editor-linux lunar-editor" ;;
+    *)      CANDIDATES="lunar-editor" ;;
+esac
+BINARY=""
+for name in $CANDIDATES; do
+    if [ -x "$SCRIPT_DIR/$name" ]; then
+        BINARY="$SCRIPT_DIR/$name"
+        break
+    fi
+done
+if [ -z "$BINARY" ]; then
+    echo "  No lunar-editor binary found in $SCRIPT_DIR (see BUILDING.md)" >&2
+    exit 1
+fi
 
-# Copy hook scripts
-cp "$SCRIPT_DIR/hooks/set-waiting.sh" "$HOOKS_DIR/"
-cp "$SCRIPT_DIR/hooks/clear-waiting.sh" "$HOOKS_DIR/"
-chmod +x "$HOOKS_DIR/set-waiting.sh" "$HOOKS_DIR/clear-waiting.sh"
+# Copy the binary where hooks.json expects it
+mkdir -p "$CLAUDE_DIR"
+cp "$BINARY" "$CLAUDE_DIR/lunar-editor"
+chmod +x "$CLAUDE_DIR/lunar-editor"
 
-echo "  Copied hook scripts to $HOOKS_DIR"
+echo "  Copied $(basename "$BINARY") to $CLAUDE_DIR/lunar-editor"
 
 # Merge hooks into settings.json
 if [ -f "$SETTINGS_FILE" ]; then
@@ -8,7 +8,12 @@ echo "$input" >> /tmp/statusline-debug.json
 
 # === Load Config ===
 CONFIG_FILE="$HOME/.claude/.statusline.config"
-STATE_FILE="$HOME/.claude/.statusline-state.json"
+
+# Waiting state is kept per session, in the file the hooks write for this
+# session id (see state.SessionKey in the Go code)
+SESSION_KEY=$(echo "$input" | jq -r '.session_id // empty' | tr -cd 'A-Za-z0-9._-' | sed 's/^\.*//')
+[ -z "$SESSION_KEY" ] && SESSION_KEY="default"
+STATE_FILE="$HOME/.claude/.statusline-state.d/${SESSION_KEY}.json"
 
 # Helper function to read config values with defaults
 cfg() {
@@ -30,7 +35,8 @@ cfg_bool() {
     local default="$2"
     if [ -f "$CONFIG_FILE" ]; then
         local value
-        value=$(jq -r "$path // empty" "$CONFIG_FILE" 2>/dev/null)
+        # Not "// empty": jq's alternative operator treats false as missing too
+        value=$(jq -r "$path" "$CONFIG_FILE" 2>/dev/null)
         if [ "$value" = "true" ]; then
             echo "true"
             return
@@ -79,7 +85,7 @@ MOON_4=$(cfg_array '.icons.moons' 3 '◕')
 MOON_5=$(cfg_array '.icons.moons' 4 '○')
 
 # === Read display settings ===
-SEPARATOR=$(cfg '.display.separator' ' • ')
+SEPARATOR=$(cfg '.display.separator' ' │ ')
 DIR_MAX_LEN=$(cfg '.thresholds.directory_max_length' '15')
 DIR_TRUNCATE=$(cfg '.thresholds.directory_truncate_to' '12')
 
@@ -117,9 +123,17 @@ if [ "$SHOW_WAITING" = "true" ] && [ -f "$STATE_FILE" ]; then
         NOW=$(date +%s)
         WAIT_SECS=$((NOW - WAIT_TS))
 
-        # Auto-clear stale waiting state (workaround for missing cancel hooks)
+        # Auto-clear stale waiting state (workaround for missing cancel hooks).
+        # Only the flag is cleared, under the session's lock, so the alerts
+        # that already fired are remembered.
         if [ "$WAIT_SECS" -gt "$WAITING_TIMEOUT" ]; then
-            rm -f "$STATE_FILE"
+            if mkdir "$STATE_FILE.lock" 2>/dev/null; then
+                TMP_STATE=$(mktemp "$(dirname "$STATE_FILE")/.tmp-XXXXXX") &&
+                    jq '.waiting = false' "$STATE_FILE" > "$TMP_STATE" 2>/dev/null &&
+                    mv "$TMP_STATE" "$STATE_FILE"
+                rm -f "$TMP_STATE"
+                rmdir "$STATE_FILE.lock"
+            fi
         else
             # Format wait time
             if [ "$WAIT_SECS" -lt 60 ]; then
@@ -364,23 +378,55 @@ if [ "$SHOW_MASCOT" = "true" ]; then
 fi
 
 # === Compose Status Line ===
-# Build parts array
-PARTS=()
-# Waiting indicator comes first (highest priority)
-[ -n "$WAITING_INFO" ] && PARTS+=("$WAITING_INFO")
-[ -n "$GIT_INFO" ] && PARTS+=("$GIT_INFO")
-[ -n "$DIR_INFO" ] && PARTS+=("$DIR_INFO")
-[ -n "$MODEL_INFO" ] && PARTS+=("$MODEL_INFO")
-[ -n "$CONTEXT_INFO" ] && PARTS+=("$CONTEXT_INFO")
-[ -n "$MASCOT" ] && PARTS+=("$MASCOT")
-
-# Join with separator
-OUTPUT=""
-for i in "${!PARTS[@]}"; do
-    if [ "$i" -gt 0 ]; then
-        OUTPUT+=" │ "
-    fi
-    OUTPUT+="${PARTS[$i]}"
-done
+# join_parts joins its arguments with the separator in $SEP
+join_parts() {
+    local out="" part
+    for part in "$@"; do
+        [ -z "$part" ] && continue
+        [ -n "$out" ] && out+="$SEP"
+        out+="$part"
+    done
+    echo "$out"
+}
+
+# section_info prints the rendered text of a section; the context sections share one part
+section_info() {
+    case "$1" in
+        waiting_indicator) echo "$WAITING_INFO" ;;
+        git) echo "$GIT_INFO" ;;
+        directory) echo "$DIR_INFO" ;;
+        model) echo "$MODEL_INFO" ;;
+        context_moons|token_count|percentage) echo "$CONTEXT_INFO" ;;
+        mascot) echo "$MASCOT" ;;
+    esac
+}
+
+LINE_COUNT=$(cfg '.display.lines | length' "0")
+if [ "$LINE_COUNT" -gt 0 ] 2>/dev/null; then
+    # One output line per row of display.lines
+    OUTPUT=""
+    for ((row = 0; row < LINE_COUNT; row++)); do
+        SEP=$(cfg ".display.lines[$row].separator" "$SEPARATOR")
+        ROW_PARTS=()
+        SHOWN_CONTEXT=false
+        while IFS= read -r name; do
+            case "$name" in
+                context_moons|token_count|percentage)
+                    [ "$SHOWN_CONTEXT" = "true" ] && continue
+                    SHOWN_CONTEXT=true ;;
+            esac
+            ROW_PARTS+=("$(section_info "$name")")
+        done < <(jq -r ".display.lines[$row].sections[]?" "$CONFIG_FILE" 2>/dev/null)
+        ROW=$(join_parts "${ROW_PARTS[@]}")
+        if [ -n "$ROW" ]; then
+            [ -n "$OUTPUT" ] && OUTPUT+="\n"
+            OUTPUT+="$ROW"
+        fi
+    done
+else
+    # Waiting indicator comes first (highest priority)
+    SEP="$SEPARATOR"
+    OUTPUT=$(join_parts "$WAITING_INFO" "$GIT_INFO" "$DIR_INFO" "$MODEL_INFO" "$CONTEXT_INFO" "$MASCOT")
+fi
 
 echo -e "$OUTPUT"
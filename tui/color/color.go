@@ -0,0 +1,240 @@
+// Package color resolves the color values used in the config — named ANSI
+// colors, 256-color indexes and #RRGGBB truecolor — to terminal output.
+package color
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Profile is the color depth supported by the terminal
+type Profile int
+
+const (
+	NoColor Profile = iota
+	ANSI
+	ANSI256
+	TrueColor
+)
+
+// DetectProfile works out the color depth from NO_COLOR, COLORTERM and TERM
+func DetectProfile() Profile {
+	return profileFromEnv(os.Getenv)
+}
+
+func profileFromEnv(getenv func(string) string) Profile {
+	if getenv("NO_COLOR") != "" {
+		return NoColor
+	}
+	term := strings.ToLower(getenv("TERM"))
+	if term == "dumb" {
+		return NoColor
+	}
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	if strings.Contains(term, "256color") || strings.Contains(term, "truecolor") {
+		return ANSI256
+	}
+	return ANSI
+}
+
+type kind int
+
+const (
+	kindDefault kind = iota
+	kindANSI
+	kindANSI256
+	kindRGB
+)
+
+// Color is a parsed color value
+type Color struct {
+	kind    kind
+	index   int
+	r, g, b uint8
+}
+
+// names maps the named colors to their ANSI index
+var names = map[string]int{
+	"black": 0, "red": 1, "green": 2, "yellow": 3,
+	"blue": 4, "magenta": 5, "cyan": 6, "white": 7,
+	"bright_black": 8, "bright_red": 9, "bright_green": 10, "bright_yellow": 11,
+	"bright_blue": 12, "bright_magenta": 13, "bright_cyan": 14, "bright_white": 15,
+	"gray": 8, "grey": 8,
+}
+
+// Names lists the named colors in palette order, starting with "default"
+func Names() []string {
+	return []string{
+		"default",
+		"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+		"bright_black", "bright_red", "bright_green", "bright_yellow",
+		"bright_blue", "bright_magenta", "bright_cyan", "bright_white",
+	}
+}
+
+// Parse reads a color name ("bright_blue"), a 256-color index ("208") or a
+// truecolor value ("#ff8800"). An empty string or "default" leaves the
+// terminal's color unchanged.
+func Parse(s string) (Color, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, "-", "_")
+	if s == "" || s == "default" || s == "none" {
+		return Color{}, nil
+	}
+	if idx, ok := names[s]; ok {
+		return Color{kind: kindANSI, index: idx}, nil
+	}
+	if strings.HasPrefix(s, "#") {
+		hex := s[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) != 6 {
+			return Color{}, fmt.Errorf("invalid color %q: expected #RRGGBB", s)
+		}
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return Color{}, fmt.Errorf("invalid color %q: expected #RRGGBB", s)
+		}
+		return Color{kind: kindRGB, r: uint8(v >> 16), g: uint8(v >> 8), b: uint8(v)}, nil
+	}
+	if idx, err := strconv.Atoi(s); err == nil {
+		if idx < 0 || idx > 255 {
+			return Color{}, fmt.Errorf("invalid color %q: index must be 0-255", s)
+		}
+		return Color{kind: kindANSI256, index: idx}, nil
+	}
+	return Color{}, fmt.Errorf("unknown color %q", s)
+}
+
+// IsDefault reports whether the color leaves the terminal's color unchanged
+func (c Color) IsDefault() bool {
+	return c.kind == kindDefault
+}
+
+// Foreground returns the SGR sequence setting c as text color, degraded to the profile
+func (c Color) Foreground(p Profile) string {
+	return c.sgr(p, false)
+}
+
+// Background returns the SGR sequence setting c as background color, degraded to the profile
+func (c Color) Background(p Profile) string {
+	return c.sgr(p, true)
+}
+
+func (c Color) sgr(p Profile, background bool) string {
+	if c.kind == kindDefault || p == NoColor {
+		return ""
+	}
+	c = c.degrade(p)
+
+	base := 38
+	if background {
+		base = 48
+	}
+	switch c.kind {
+	case kindRGB:
+		return fmt.Sprintf("\033[%d;2;%d;%d;%dm", base, c.r, c.g, c.b)
+	case kindANSI256:
+		return fmt.Sprintf("\033[%d;5;%dm", base, c.index)
+	}
+
+	code := 30 + c.index
+	if c.index >= 8 {
+		code = 90 + c.index - 8
+	}
+	if background {
+		code += 10
+	}
+	return fmt.Sprintf("\033[%dm", code)
+}
+
+// degrade converts c to the richest kind the profile supports
+func (c Color) degrade(p Profile) Color {
+	switch {
+	case c.kind == kindRGB && p == ANSI256:
+		return Color{kind: kindANSI256, index: nearest(c.r, c.g, c.b, 16, 256)}
+	case c.kind == kindRGB && p == ANSI:
+		return Color{kind: kindANSI, index: nearest(c.r, c.g, c.b, 0, 16)}
+	case c.kind == kindANSI256 && p == ANSI && c.index >= 16:
+		r, g, b := paletteRGB(c.index)
+		return Color{kind: kindANSI, index: nearest(r, g, b, 0, 16)}
+	case c.kind == kindANSI256 && c.index < 16:
+		return Color{kind: kindANSI, index: c.index}
+	}
+	return c
+}
+
+// Lipgloss returns the color for use in lipgloss styles
+func (c Color) Lipgloss() lipgloss.TerminalColor {
+	switch c.kind {
+	case kindANSI, kindANSI256:
+		return lipgloss.Color(strconv.Itoa(c.index))
+	case kindRGB:
+		return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b))
+	}
+	return lipgloss.NoColor{}
+}
+
+// Paint wraps text in the foreground color named by value. Invalid or
+// default colors leave the text unchanged.
+func Paint(p Profile, value, text string) string {
+	c, err := Parse(value)
+	if err != nil {
+		return text
+	}
+	seq := c.Foreground(p)
+	if seq == "" {
+		return text
+	}
+	return seq + text + Reset
+}
+
+// Reset clears all text attributes
+const Reset = "\033[0m"
+
+// ansi16 holds the xterm default RGB values of the 16 basic colors
+var ansi16 = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// paletteRGB returns the RGB value of a 256-color palette index
+func paletteRGB(idx int) (uint8, uint8, uint8) {
+	switch {
+	case idx < 16:
+		c := ansi16[idx]
+		return c[0], c[1], c[2]
+	case idx < 232:
+		idx -= 16
+		return cubeLevels[idx/36], cubeLevels[(idx/6)%6], cubeLevels[idx%6]
+	default:
+		v := uint8(8 + (idx-232)*10)
+		return v, v, v
+	}
+}
+
+// nearest finds the palette index in [from, to) closest to the given RGB value
+func nearest(r, g, b uint8, from, to int) int {
+	best, bestDist := from, -1
+	for i := from; i < to; i++ {
+		pr, pg, pb := paletteRGB(i)
+		dr, dg, db := int(r)-int(pr), int(g)-int(pg), int(b)-int(pb)
+		dist := dr*dr + dg*dg + db*db
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
@@ -0,0 +1,104 @@
+package color
+
+import "testing"
+
+func TestPaint(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile Profile
+		value   string
+		text    string
+		want    string
+	}{
+		{"named", ANSI, "green", "ok", "\033[32mok\033[0m"},
+		{"bright", ANSI, "bright_red", "x", "\033[91mx\033[0m"},
+		{"default leaves text alone", TrueColor, "default", "x", "x"},
+		{"invalid leaves text alone", TrueColor, "chartreuse-ish", "x", "x"},
+		{"no color", NoColor, "green", "x", "x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Paint(tt.profile, tt.value, tt.text); got != tt.want {
+				t.Errorf("Paint(%v, %q, %q) = %q, want %q", tt.profile, tt.value, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		fg    string // Foreground in TrueColor
+		err   bool
+	}{
+		{"", "", false},
+		{"none", "", false},
+		{"Bright-Blue", "\033[94m", false},
+		{"grey", "\033[90m", false},
+		{"208", "\033[38;5;208m", false},
+		{"3", "\033[33m", false}, // The first 16 indexes are the named colors
+		{"#ff8800", "\033[38;2;255;136;0m", false},
+		{"#f80", "\033[38;2;255;136;0m", false},
+		{"256", "", true},
+		{"#ff88", "", true},
+		{"#gg8800", "", true},
+		{"chartreuse", "", true},
+	}
+	for _, tt := range tests {
+		c, err := Parse(tt.value)
+		if (err != nil) != tt.err {
+			t.Errorf("Parse(%q) error = %v, want error %v", tt.value, err, tt.err)
+			continue
+		}
+		if got := c.Foreground(TrueColor); got != tt.fg {
+			t.Errorf("Parse(%q) foreground = %q, want %q", tt.value, got, tt.fg)
+		}
+	}
+}
+
+func TestDegrade(t *testing.T) {
+	tests := []struct {
+		value   string
+		profile Profile
+		want    string
+	}{
+		{"#ff8800", ANSI256, "\033[38;5;208m"},
+		{"#ff0000", ANSI, "\033[91m"},
+		{"#000000", ANSI, "\033[30m"},
+		{"208", ANSI, "\033[33m"},
+		{"238", ANSI, "\033[90m"},
+		{"bright_red", ANSI256, "\033[91m"},
+	}
+	for _, tt := range tests {
+		c, err := Parse(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Foreground(tt.profile); got != tt.want {
+			t.Errorf("%s in profile %d = %q, want %q", tt.value, tt.profile, got, tt.want)
+		}
+	}
+
+	c, _ := Parse("bright_green")
+	if got := c.Background(ANSI); got != "\033[102m" {
+		t.Errorf("bright_green background = %q, want \\033[102m", got)
+	}
+}
+
+func TestProfileFromEnv(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want Profile
+	}{
+		{map[string]string{"TERM": "xterm"}, ANSI},
+		{map[string]string{"TERM": "xterm-256color"}, ANSI256},
+		{map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, TrueColor},
+		{map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1"}, NoColor},
+		{map[string]string{"TERM": "dumb", "COLORTERM": "24bit"}, NoColor},
+	}
+	for _, tt := range tests {
+		if got := profileFromEnv(func(key string) string { return tt.env[key] }); got != tt.want {
+			t.Errorf("profile for %v = %d, want %d", tt.env, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxBackups is how many earlier versions of the config file are kept
+const MaxBackups = 10
+
+// backupTimeFormat names backup files by when their version was saved
+const backupTimeFormat = "20060102-150405.000"
+
+// Backup is an earlier saved version of the config file
+type Backup struct {
+	Path  string
+	Saved time.Time
+}
+
+// BackupDir returns where earlier versions of the config at path are kept,
+// e.g. ~/.claude/.statusline.config.d/backups
+func BackupDir(path string) string {
+	return filepath.Join(path+".d", "backups")
+}
+
+// ListBackups returns the backups of the config at path, newest first
+func ListBackups(path string) ([]Backup, error) {
+	entries, err := os.ReadDir(BackupDir(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".json")
+		saved, err := time.ParseInLocation(backupTimeFormat, name, time.Local)
+		if e.IsDir() || name == e.Name() || err != nil {
+			continue
+		}
+		backups = append(backups, Backup{Path: filepath.Join(BackupDir(path), e.Name()), Saved: saved})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Saved.After(backups[j].Saved) })
+	return backups, nil
+}
+
+// RestoreBackup puts a backup back in place of the config at path. The
+// version it replaces is backed up in turn.
+func RestoreBackup(path string, backup Backup) error {
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return err
+	}
+	return writeConfig(path, data)
+}
+
+// writeConfig replaces the config file at path with data, first moving the
+// version it replaces into the backups
+func writeConfig(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := backupConfig(path, data); err != nil {
+		return err
+	}
+	return writeAtomic(path, data, 0644)
+}
+
+// backupConfig copies the config at path into its backups unless it is
+// missing or already holds data, then drops all but the newest MaxBackups
+func backupConfig(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(current, data) {
+		return nil
+	}
+
+	dir := BackupDir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	name := info.ModTime().Format(backupTimeFormat) + ".json"
+	if err := writeAtomic(filepath.Join(dir, name), current, 0644); err != nil {
+		return err
+	}
+
+	backups, err := ListBackups(path)
+	if err != nil {
+		return err
+	}
+	for i := MaxBackups; i < len(backups); i++ {
+		os.Remove(backups[i].Path)
+	}
+	return nil
+}
+
+// writeAtomic writes data to a temp file next to path, syncs it and renames
+// it over path, so that readers see either the old file or the whole new one.
+// A symlinked path, e.g. into a dotfiles repository, has its target replaced.
+func writeAtomic(path string, data []byte, perm os.FileMode) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Make the rename itself durable; not every platform can sync a directory
+	if dir, err := os.Open(filepath.Dir(path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// saveVersion writes data as the config at path, dating the version it
+// replaces at saved so each backup gets its own name
+func saveVersion(t *testing.T, path, data string, saved time.Time) {
+	t.Helper()
+	if _, err := os.Stat(path); err == nil {
+		if err := os.Chtimes(path, saved, saved); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writeConfig(path, []byte(data)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeJSON writes data to path, creating its directory
+func writeJSON(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readString(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestWriteConfigBacksUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+
+	saveVersion(t, path, `{"v":1}`, start)
+	if backups, _ := ListBackups(path); len(backups) != 0 {
+		t.Errorf("the first save made %d backups, want none", len(backups))
+	}
+
+	saveVersion(t, path, `{"v":2}`, start.Add(time.Minute))
+	saveVersion(t, path, `{"v":2}`, start.Add(2*time.Minute))
+	backups, err := ListBackups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("got %d backups, want 1: saving the same data again keeps none", len(backups))
+	}
+	if got := readString(t, backups[0].Path); got != `{"v":1}` {
+		t.Errorf("backup holds %s, want the replaced version", got)
+	}
+	if !backups[0].Saved.Equal(start.Add(time.Minute)) {
+		t.Errorf("backup dated %v, want %v", backups[0].Saved, start.Add(time.Minute))
+	}
+}
+
+func TestBackupRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	for i := 0; i <= MaxBackups+2; i++ {
+		saveVersion(t, path, fmt.Sprintf(`{"v":%d}`, i), start.Add(time.Duration(i)*time.Minute))
+	}
+
+	backups, err := ListBackups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != MaxBackups {
+		t.Fatalf("kept %d backups, want %d", len(backups), MaxBackups)
+	}
+	// Newest first: the version saved just before the current one
+	if got, want := readString(t, backups[0].Path), fmt.Sprintf(`{"v":%d}`, MaxBackups+1); got != want {
+		t.Errorf("newest backup holds %s, want %s", got, want)
+	}
+	if got := readString(t, backups[MaxBackups-1].Path); got != `{"v":2}` {
+		t.Errorf("oldest backup holds %s, want {\"v\":2}", got)
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	saveVersion(t, path, `{"v":1}`, start)
+	saveVersion(t, path, `{"v":2}`, start.Add(time.Minute))
+
+	backups, _ := ListBackups(path)
+	os.Chtimes(path, start.Add(2*time.Minute), start.Add(2*time.Minute))
+	if err := RestoreBackup(path, backups[0]); err != nil {
+		t.Fatal(err)
+	}
+	if got := readString(t, path); got != `{"v":1}` {
+		t.Errorf("restored config holds %s, want {\"v\":1}", got)
+	}
+	backups, _ = ListBackups(path)
+	if len(backups) != 2 || readString(t, backups[0].Path) != `{"v":2}` {
+		t.Errorf("the replaced version was not backed up: %v", backups)
+	}
+}
+
+func TestListBackupsIgnoresOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	writeJSON(t, filepath.Join(BackupDir(path), "notes.json"), `{}`)
+	writeJSON(t, filepath.Join(BackupDir(path), "20260301-090000.000"), `{}`)
+	writeJSON(t, filepath.Join(BackupDir(path), "20260301-090000.000.json"), `{}`)
+
+	backups, err := ListBackups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Errorf("ListBackups = %v, want only the dated .json file", backups)
+	}
+}
+
+func TestWriteAtomicFollowsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "statusline.json")
+	link := filepath.Join(dir, ConfigFileName)
+	writeJSON(t, target, `{"v":1}`)
+	if err := os.Symlink(target, link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	if err := writeAtomic(link, []byte(`{"v":2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Error("the symlink was replaced by a file")
+	}
+	if got := readString(t, target); got != `{"v":2}` {
+		t.Errorf("target holds %s, want the new data", got)
+	}
+}
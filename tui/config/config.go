@@ -1,16 +1,31 @@
 package config
 
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
 // Config represents the complete statusline configuration
 type Config struct {
 	Version          string           `json:"version"`
 	EnabledSections  EnabledSections  `json:"enabled_sections"`
+	SectionOrder     []string         `json:"section_order,omitempty"`
 	Colors           Colors           `json:"colors"`
 	Icons            Icons            `json:"icons"`
 	Mascot           Mascot           `json:"mascot"`
 	Thresholds       Thresholds       `json:"thresholds"`
+	Budget           Budget           `json:"budget"`
+	Git              Git              `json:"git"`
 	Display          Display          `json:"display"`
 	WaitingIndicator WaitingIndicator `json:"waiting_indicator"`
 	Notifications    Notifications    `json:"notifications"`
+
+	// Migrations lists the schema upgrades applied when the file was loaded
+	Migrations []Migration `json:"-"`
+	// Overlay is the project overlay merged over the global file, if any
+	Overlay string `json:"-"`
 }
 
 // WaitingIndicator settings for when Claude is waiting for user input
@@ -19,15 +34,17 @@ type WaitingIndicator struct {
 	Icon    string `json:"icon"`
 	Text    string `json:"text"`
 	Blink   bool   `json:"blink"`
+	// Timeout is how long, in seconds, a wait is shown before it is taken to be stale
+	Timeout int `json:"timeout"`
 }
 
 // Notifications settings for alerts when Claude needs input
 type Notifications struct {
-	TerminalBell  NotificationConfig `json:"terminal_bell"`
+	TerminalBell  NotificationConfig  `json:"terminal_bell"`
 	Desktop       DesktopNotification `json:"desktop"`
-	BlinkingText  NotificationConfig `json:"blinking_text"`
+	BlinkingText  NotificationConfig  `json:"blinking_text"`
 	TerminalTitle TerminalTitleConfig `json:"terminal_title"`
-	Tmux          TmuxNotification `json:"tmux"`
+	Tmux          TmuxNotification    `json:"tmux"`
 }
 
 // NotificationConfig represents a basic notification type
@@ -61,6 +78,8 @@ type TerminalTitleConfig struct {
 	AlertOnPanic     bool   `json:"alert_on_panic"`
 	PanicPrefix      string `json:"panic_prefix"`
 	ContextThreshold int    `json:"context_threshold"`
+	// Template overrides the Show* flags, e.g. "{model} · {context} · {branch}"
+	Template string `json:"template,omitempty"`
 }
 
 // TmuxNotification for tmux-specific notifications
@@ -75,33 +94,194 @@ type TmuxNotification struct {
 	AlertStyle       string `json:"alert_style"`
 }
 
-// EnabledSections controls which sections are displayed
-type EnabledSections struct {
-	Git              bool `json:"git"`
-	Directory        bool `json:"directory"`
-	Model            bool `json:"model"`
-	ContextMoons     bool `json:"context_moons"`
-	TokenCount       bool `json:"token_count"`
-	Percentage       bool `json:"percentage"`
-	Mascot           bool `json:"mascot"`
-	WaitingIndicator bool `json:"waiting_indicator"`
+// EnabledSections switches sections on and off by segment name. Sections it
+// does not mention keep their default from defaultSections.
+type EnabledSections map[string]bool
+
+// Enabled reports whether the named section is switched on
+func (e EnabledSections) Enabled(name string) bool {
+	if enabled, ok := e[name]; ok {
+		return enabled
+	}
+	return defaultSections()[name]
+}
+
+// defaultSections returns whether each built-in section is shown by default.
+// Every segment render registers needs an entry here.
+func defaultSections() EnabledSections {
+	return EnabledSections{
+		"waiting_indicator": true,
+		"git":               true,
+		"git_ahead_behind":  true,
+		"git_staged":        true,
+		"git_modified":      true,
+		"git_untracked":     true,
+		"git_stash":         false,
+		"git_operation":     true,
+		"directory":         true,
+		"model":             true,
+		"context_moons":     true,
+		"token_count":       true,
+		"percentage":        true,
+		"budget":            false,
+		"cost":              false,
+		"duration":          false,
+		"api_time":          false,
+		"lines":             false,
+		"mascot":            true,
+	}
 }
 
 // Colors defines the color scheme
 type Colors struct {
-	Directory string `json:"directory"`
-	GitClean  string `json:"git_clean"`
-	GitDirty  string `json:"git_dirty"`
-	Model     string `json:"model"`
-	Text      string `json:"text"`
+	Directory  string `json:"directory"`
+	GitClean   string `json:"git_clean"`
+	GitDirty   string `json:"git_dirty"`
+	Model      string `json:"model"`
+	Text       string `json:"text"`
+	BudgetOK   string `json:"budget_ok"`
+	BudgetWarn string `json:"budget_warn"`
+	BudgetOver string `json:"budget_over"`
+
+	// Segment backgrounds in the powerline style
+	WaitingBg   string `json:"waiting_bg"`
+	GitBg       string `json:"git_bg"`
+	DirectoryBg string `json:"directory_bg"`
+	ModelBg     string `json:"model_bg"`
+	ContextBg   string `json:"context_bg"`
+	SessionBg   string `json:"session_bg"`
+	MascotBg    string `json:"mascot_bg"`
+}
+
+// Field returns a pointer to the color with the given JSON key, or nil if there is none
+func (c *Colors) Field(key string) *string {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if strings.Split(t.Field(i).Tag.Get("json"), ",")[0] == key {
+			return v.Field(i).Addr().Interface().(*string)
+		}
+	}
+	return nil
 }
 
 // Icons defines the emoji/icon set
 type Icons struct {
-	GitClean  string   `json:"git_clean"`
-	GitDirty  string   `json:"git_dirty"`
-	Directory string   `json:"directory"`
-	Moons     []string `json:"moons"`
+	GitClean     string   `json:"git_clean"`
+	GitDirty     string   `json:"git_dirty"`
+	Directory    string   `json:"directory"`
+	Moons        []string `json:"moons"`
+	Budget       string   `json:"budget"`
+	Cost         string   `json:"cost"`
+	Duration     string   `json:"duration"`
+	APITime      string   `json:"api_time"`
+	Lines        string   `json:"lines"`
+	GitAhead     string   `json:"git_ahead"`
+	GitBehind    string   `json:"git_behind"`
+	GitStaged    string   `json:"git_staged"`
+	GitModified  string   `json:"git_modified"`
+	GitUntracked string   `json:"git_untracked"`
+	GitStash     string   `json:"git_stash"`
+	GitDetached  string   `json:"git_detached"`
+	GitOperation string   `json:"git_operation"`
+	GitStale     string   `json:"git_stale"`
+}
+
+// Icon presets
+const (
+	IconsEmoji    = "emoji"
+	IconsNerdFont = "nerd_font"
+)
+
+// IconPresets lists the available icon presets
+func IconPresets() []string {
+	return []string{IconsEmoji, IconsNerdFont}
+}
+
+// IconPreset returns the icon set of a preset; unknown names give the emoji set
+func IconPreset(name string) Icons {
+	if name == IconsNerdFont {
+		// Nerd Font glyphs: Devicons, Octicons and Font Awesome
+		return Icons{
+			GitClean:     "\ue725", // nf-dev-git_branch
+			GitDirty:     "\ue725",
+			Directory:    "\uf07c", // nf-fa-folder_open
+			Moons:        []string{"○", "◔", "◑", "◕", "●"},
+			Budget:       "\uf0d6", // nf-fa-money
+			Cost:         "\uf155", // nf-fa-dollar
+			Duration:     "\uf017", // nf-fa-clock_o
+			APITime:      "\uf0e7", // nf-fa-bolt
+			Lines:        "\uf040", // nf-fa-pencil
+			GitAhead:     "⇡",
+			GitBehind:    "⇣",
+			GitStaged:    "●",
+			GitModified:  "✚",
+			GitUntracked: "?",
+			GitStash:     "\uf187", // nf-fa-archive
+			GitDetached:  "\uf417", // nf-oct-git_commit
+			GitOperation: "\uf0ad", // nf-fa-wrench
+			GitStale:     "\uf252", // nf-fa-hourglass_half
+		}
+	}
+	return Icons{
+		GitClean:     "✅",
+		GitDirty:     "⚠️",
+		Directory:    "🗂️",
+		Moons:        []string{"🌑", "🌘", "🌗", "🌖", "🌕"},
+		Budget:       "💰",
+		Cost:         "💵",
+		Duration:     "⏱️",
+		APITime:      "🧠",
+		Lines:        "📝",
+		GitAhead:     "↑",
+		GitBehind:    "↓",
+		GitStaged:    "●",
+		GitModified:  "✚",
+		GitUntracked: "…",
+		GitStash:     "⚑",
+		GitDetached:  "➦",
+		GitOperation: "🚧",
+		GitStale:     "⏳",
+	}
+}
+
+// ClosestPreset returns the preset sharing the most icons with i
+func (i Icons) ClosestPreset() string {
+	best, bestCount := IconsEmoji, -1
+	for _, name := range IconPresets() {
+		if n := i.matching(IconPreset(name)); n > bestCount {
+			best, bestCount = name, n
+		}
+	}
+	return best
+}
+
+// SwitchPreset moves the icons from preset from to preset to. Icons that no
+// longer match from were customized and are kept; it returns how many.
+func (i *Icons) SwitchPreset(from, to string) int {
+	old, next := reflect.ValueOf(IconPreset(from)), reflect.ValueOf(IconPreset(to))
+	v := reflect.ValueOf(i).Elem()
+	kept := 0
+	for f := 0; f < v.NumField(); f++ {
+		if !reflect.DeepEqual(v.Field(f).Interface(), old.Field(f).Interface()) {
+			kept++
+			continue
+		}
+		v.Field(f).Set(next.Field(f))
+	}
+	return kept
+}
+
+// matching counts the icons i shares with other
+func (i Icons) matching(other Icons) int {
+	a, b := reflect.ValueOf(i), reflect.ValueOf(other)
+	n := 0
+	for f := 0; f < a.NumField(); f++ {
+		if reflect.DeepEqual(a.Field(f).Interface(), b.Field(f).Interface()) {
+			n++
+		}
+	}
+	return n
 }
 
 // Mascot defines the mascot behavior settings
@@ -117,8 +297,8 @@ type MascotState struct {
 	Enabled   bool     `json:"enabled"`
 	Threshold int      `json:"threshold"`
 	Emojis    []string `json:"emojis"`
-	Animate   bool     `json:"animate"`   // If true, cycle through emojis as animation frames
-	Speed     int      `json:"speed"`     // Animation speed in milliseconds (default 500)
+	Animate   bool     `json:"animate"` // If true, cycle through emojis as animation frames
+	Speed     int      `json:"speed"`   // Animation speed in milliseconds (default 500)
 }
 
 // TimeBasedMood represents time-of-day moods
@@ -128,8 +308,8 @@ type TimeBasedMood struct {
 	Morning   []string `json:"morning"`
 	Afternoon []string `json:"afternoon"`
 	Evening   []string `json:"evening"`
-	Animate   bool     `json:"animate"`   // If true, cycle through emojis as animation frames
-	Speed     int      `json:"speed"`     // Animation speed in milliseconds (default 500)
+	Animate   bool     `json:"animate"` // If true, cycle through emojis as animation frames
+	Speed     int      `json:"speed"`   // Animation speed in milliseconds (default 500)
 }
 
 // Thresholds defines various threshold values
@@ -140,38 +320,177 @@ type Thresholds struct {
 	TokenKFormat        int   `json:"token_k_format"`
 }
 
+// Phase maps a context percentage to a moon phase index, from 0 below the
+// first threshold up to len(MoonPhases) at or above the last one
+func (t Thresholds) Phase(pct int) int {
+	phase := 0
+	for _, threshold := range t.MoonPhases {
+		if pct >= threshold {
+			phase++
+		}
+	}
+	return phase
+}
+
+// PhaseRange returns the percentage range [lo, hi) covered by a moon phase
+func (t Thresholds) PhaseRange(phase int) (lo, hi int) {
+	lo, hi = 0, 100
+	if phase > 0 && phase-1 < len(t.MoonPhases) {
+		lo = t.MoonPhases[phase-1]
+	}
+	if phase < len(t.MoonPhases) {
+		hi = t.MoonPhases[phase]
+	}
+	return lo, hi
+}
+
+// ValidateMoonPhases checks that moon phase thresholds are within 0-100 and strictly increasing
+func ValidateMoonPhases(phases []int) error {
+	for i, threshold := range phases {
+		if threshold < 0 || threshold > 100 {
+			return fmt.Errorf("threshold %d must be between 0 and 100", threshold)
+		}
+		if i > 0 && threshold <= phases[i-1] {
+			return fmt.Errorf("thresholds must increase: %d comes after %d", threshold, phases[i-1])
+		}
+	}
+	return nil
+}
+
+// Budget caps what a single session may spend, as reported in cost.total_cost_usd
+type Budget struct {
+	SessionUSD float64 `json:"session_usd"` // 0 disables the budget
+	WarnAt     int     `json:"warn_at"`     // Percent of the budget from which it is shown as a warning
+}
+
+// Percent returns how much of the budget has been spent, or 0 when there is no budget
+func (b Budget) Percent(spent float64) int {
+	if b.SessionUSD <= 0 {
+		return 0
+	}
+	return int(spent / b.SessionUSD * 100)
+}
+
+// Git controls how the git segments read the repository
+type Git struct {
+	CacheTTL  int `json:"cache_ttl"`  // Seconds a status is reused while .git/index and .git/HEAD are unchanged
+	TimeoutMs int `json:"timeout_ms"` // Deadline for git, after which the last known status is shown as stale
+}
+
 // Display defines display formatting options
 type Display struct {
-	Separator string `json:"separator"`
+	Separator     string `json:"separator"`
+	Gauge         Gauge  `json:"gauge"`
+	CostPrecision int    `json:"cost_precision"` // Decimal places shown for the session cost
+	// Style is plain text or powerline, one of DisplayStyles
+	Style string `json:"style"`
+	// DirectoryStyle picks how workspace.current_dir is shown, one of DirectoryStyles
+	DirectoryStyle string `json:"directory_style"`
+	// MaxWidth caps the line in terminal cells; 0 fits $COLUMNS only
+	MaxWidth int `json:"max_width"`
+	// Priorities override the built-in priority of a section; the lowest
+	// priorities are shortened or dropped first when the line is too wide
+	Priorities map[string]int `json:"priorities,omitempty"`
+	// Lines splits the statusline into rows; when empty there is a single row of
+	// all enabled sections in SectionOrder
+	Lines []Line `json:"lines,omitempty"`
+	// Format is a text/template for the whole line, e.g. "{{.Git}} {{.Dir}} ⟶ {{.Model}}";
+	// when set it replaces the enabled sections, their order and the separator
+	Format string `json:"format,omitempty"`
+}
+
+// Directory segment styles
+const (
+	DirectoryBasename = "basename" // Last path element only
+	DirectoryRelative = "relative" // Path from the project root, e.g. project/src/api
+	DirectoryFish     = "fish"     // Parents cut to their first letter, e.g. ~/w/p/api
+	DirectoryEllipsis = "ellipsis" // Full path with the middle elided when too long
+)
+
+// DirectoryStyles lists the available directory segment styles
+func DirectoryStyles() []string {
+	return []string{DirectoryBasename, DirectoryRelative, DirectoryFish, DirectoryEllipsis}
+}
+
+// Display styles
+const (
+	DisplayPlain     = "plain"     // Colored text joined by the separator
+	DisplayPowerline = "powerline" // Segments on colored backgrounds joined by arrows; needs a Nerd Font
+)
+
+// DisplayStyles lists the available display styles
+func DisplayStyles() []string {
+	return []string{DisplayPlain, DisplayPowerline}
+}
+
+// Line is one row of a multi-line statusline
+type Line struct {
+	Sections  []string `json:"sections"`            // Section names in display order
+	Separator string   `json:"separator,omitempty"` // Overrides Display.Separator for this row
+}
+
+// Context gauge styles
+const (
+	GaugeMoons   = "moons"
+	GaugeBlocks  = "blocks"
+	GaugeBraille = "braille"
+	GaugeASCII   = "ascii"
+)
+
+// GaugeStyles lists the available context gauge styles
+func GaugeStyles() []string {
+	return []string{GaugeMoons, GaugeBlocks, GaugeBraille, GaugeASCII}
+}
+
+// defaultGaugeWidths is the number of cells each gauge style uses unless configured
+var defaultGaugeWidths = map[string]int{
+	GaugeMoons:   3,
+	GaugeBlocks:  8,
+	GaugeBraille: 5,
+	GaugeASCII:   10,
+}
+
+// Gauge defines how the context usage gauge is drawn
+type Gauge struct {
+	Style  string         `json:"style"`
+	Widths map[string]int `json:"widths"`
+}
+
+// Width returns the configured number of cells for a gauge style
+func (g Gauge) Width(style string) int {
+	if w, ok := g.Widths[style]; ok && w > 0 {
+		return w
+	}
+	if w, ok := defaultGaugeWidths[style]; ok {
+		return w
+	}
+	return defaultGaugeWidths[GaugeMoons]
 }
 
 // DefaultConfig returns a config with sensible defaults
 func DefaultConfig() *Config {
 	return &Config{
-		Version: "1.0",
-		EnabledSections: EnabledSections{
-			Git:              true,
-			Directory:        true,
-			Model:            true,
-			ContextMoons:     true,
-			TokenCount:       true,
-			Percentage:       true,
-			Mascot:           true,
-			WaitingIndicator: true,
-		},
+		Version:         CurrentVersion,
+		EnabledSections: defaultSections(),
 		Colors: Colors{
-			Directory: "bright_blue",
-			GitClean:  "bright_green",
-			GitDirty:  "bright_red",
-			Model:     "bright_cyan",
-			Text:      "default",
-		},
-		Icons: Icons{
-			GitClean:  "✅",
-			GitDirty:  "⚠️",
-			Directory: "🗂️",
-			Moons:     []string{"🌑", "🌘", "🌗", "🌖", "🌕"},
+			Directory:  "bright_blue",
+			GitClean:   "bright_green",
+			GitDirty:   "bright_red",
+			Model:      "bright_cyan",
+			Text:       "default",
+			BudgetOK:   "bright_green",
+			BudgetWarn: "bright_yellow",
+			BudgetOver: "bright_red",
+
+			WaitingBg:   "94",
+			GitBg:       "238",
+			DirectoryBg: "237",
+			ModelBg:     "53",
+			ContextBg:   "236",
+			SessionBg:   "237",
+			MascotBg:    "235",
 		},
+		Icons: IconPreset(IconsEmoji),
 		Mascot: Mascot{
 			ContextPanic: MascotState{
 				Enabled:   true,
@@ -210,14 +529,35 @@ func DefaultConfig() *Config {
 			DirectoryTruncateTo: 12,
 			TokenKFormat:        1000,
 		},
+		Git: Git{
+			CacheTTL:  5,
+			TimeoutMs: 500,
+		},
+		Budget: Budget{
+			SessionUSD: 0,
+			WarnAt:     80,
+		},
 		Display: Display{
-			Separator: " • ",
+			Style:          DisplayPlain,
+			Separator:      " │ ",
+			CostPrecision:  2,
+			DirectoryStyle: DirectoryBasename,
+			Gauge: Gauge{
+				Style: GaugeMoons,
+				Widths: map[string]int{
+					GaugeMoons:   3,
+					GaugeBlocks:  8,
+					GaugeBraille: 5,
+					GaugeASCII:   10,
+				},
+			},
 		},
 		WaitingIndicator: WaitingIndicator{
 			Enabled: true,
 			Icon:    "🔔",
 			Text:    "WAITING",
 			Blink:   true,
+			Timeout: 300,
 		},
 		Notifications: Notifications{
 			TerminalBell: NotificationConfig{
@@ -266,3 +606,12 @@ func DefaultConfig() *Config {
 		},
 	}
 }
+
+// Clone returns a deep copy of the config's saved fields
+func (c *Config) Clone() *Config {
+	clone := &Config{}
+	if data, err := json.Marshal(c); err == nil {
+		json.Unmarshal(data, clone)
+	}
+	return clone
+}
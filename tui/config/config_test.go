@@ -0,0 +1,40 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnabledSectionsDefaults(t *testing.T) {
+	// The defaults do not depend on which packages registered segments
+	sections := DefaultConfig().EnabledSections
+	for name, want := range map[string]bool{"git": true, "model": true, "mascot": true, "cost": false, "lines": false} {
+		if got := sections[name]; got != want {
+			t.Errorf("DefaultConfig enables %s = %v, want %v", name, got, want)
+		}
+	}
+
+	if !(EnabledSections{}).Enabled("model") || (EnabledSections{}).Enabled("weather") {
+		t.Error("sections missing from the map should fall back to their default, and unknown ones stay off")
+	}
+}
+
+func TestSwitchPreset(t *testing.T) {
+	icons := IconPreset(IconsEmoji)
+	icons.Directory = "D"
+	icons.Moons = []string{"a", "b"}
+	if got := icons.ClosestPreset(); got != IconsEmoji {
+		t.Errorf("ClosestPreset = %q, want %q", got, IconsEmoji)
+	}
+
+	if kept := icons.SwitchPreset(IconsEmoji, IconsNerdFont); kept != 2 {
+		t.Errorf("SwitchPreset kept %d icons, want the 2 customized", kept)
+	}
+	nerd := IconPreset(IconsNerdFont)
+	if icons.GitClean != nerd.GitClean || icons.Cost != nerd.Cost {
+		t.Errorf("preset icons not switched: git_clean %q, cost %q", icons.GitClean, icons.Cost)
+	}
+	if icons.Directory != "D" || !reflect.DeepEqual(icons.Moons, []string{"a", "b"}) {
+		t.Errorf("custom icons overwritten: directory %q, moons %v", icons.Directory, icons.Moons)
+	}
+}
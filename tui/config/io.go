@@ -1,9 +1,12 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 )
 
 const ConfigFileName = ".statusline.config"
@@ -17,31 +20,106 @@ func GetConfigPath() (string, error) {
 	return filepath.Join(homeDir, ".claude", ConfigFileName), nil
 }
 
-// Load reads the config from the default location
+// Load reads the global config merged with the project overlay, if any, for
+// the current directory
 func Load() (*Config, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFor(dir)
+}
+
+// LoadFor reads the global config merged with the project overlay found from
+// dir up to its repository root
+func LoadFor(dir string) (*Config, error) {
 	path, err := GetConfigPath()
 	if err != nil {
 		return nil, err
 	}
-	return LoadFromPath(path)
+	return LoadWithOverlay(path, FindOverlay(dir))
 }
 
 // LoadFromPath reads the config from a specific path
 func LoadFromPath(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	return LoadWithOverlay(path, "")
+}
+
+// LoadWithOverlay reads the config at path and deep-merges the overlay file
+// over it; an empty or missing overlay leaves the config as it is
+func LoadWithOverlay(path, overlay string) (*Config, error) {
+	data, applied, err := readLayer(path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			// Return default config if file doesn't exist
-			return DefaultConfig(), nil
+		return nil, err
+	}
+
+	var merged string
+	if overlay != "" {
+		over, err := os.ReadFile(overlay)
+		switch {
+		case err == nil:
+			if over, _, err = Migrate(over); err != nil {
+				return nil, fmt.Errorf("%s: %w", overlay, err)
+			}
+			if data, err = mergeOverlay(data, over); err != nil {
+				return nil, fmt.Errorf("%s: %w", overlay, err)
+			}
+			merged = overlay
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+	}
+
+	// Start with defaults, then overlay loaded config
+	cfg := DefaultConfig()
+	if data != nil {
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, err
 		}
+	}
+	cfg.Migrations = applied
+	cfg.Overlay = merged
+
+	return cfg, nil
+}
+
+// readLayer reads a config file upgraded to the current schema. The upgrade
+// happens in memory only; the file on disk is left alone until it is saved.
+// A missing file reads as nil.
+func readLayer(path string) ([]byte, []Migration, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	migrated, applied, err := Migrate(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The file is kept once, as it was before the first step
+	if len(applied) > 0 {
+		applied[0].Backup = MigrationBackup(path, applied[0].From)
+	}
+	return migrated, applied, nil
+}
+
+// Parse reads a config file's contents over the defaults, upgrading older
+// schema versions in memory only
+func Parse(data []byte) (*Config, error) {
+	migrated, applied, err := Migrate(data)
+	if err != nil {
 		return nil, err
 	}
 
 	// Start with defaults, then overlay loaded config
 	cfg := DefaultConfig()
-	if err := json.Unmarshal(data, cfg); err != nil {
+	if err := json.Unmarshal(migrated, cfg); err != nil {
 		return nil, err
 	}
+	cfg.Migrations = applied
 
 	return cfg, nil
 }
@@ -55,84 +133,131 @@ func Save(cfg *Config) error {
 	return SaveToPath(cfg, path)
 }
 
-// SaveToPath writes the config to a specific path
+// SaveToPath writes the config to a specific path. Keys in the existing file
+// that Config does not know are kept, in their original order, and the
+// version it replaces is kept in BackupDir. A file from an older schema is
+// also kept at its MigrationBackup.
 func SaveToPath(cfg *Config, path string) error {
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	data, err := json.Marshal(cfg)
 	if err != nil {
 		return err
 	}
-
-	// Ensure directory exists
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if orig, err := os.ReadFile(path); err == nil {
+		if err := backupBeforeMigration(path, orig); err != nil {
+			return err
+		}
+		data = mergeJSON(orig, data, reflect.TypeOf(cfg))
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
 		return err
 	}
+	return writeConfig(path, out.Bytes())
+}
 
-	return os.WriteFile(path, data, 0644)
+// GetSettingsPath returns the path to Claude Code's settings.json
+func GetSettingsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".claude", "settings.json"), nil
 }
 
-// GetStatuslineScriptPath returns the path where statusline.sh should be installed
-func GetStatuslineScriptPath() (string, error) {
+// BinaryName is the file the editor installs itself as in ~/.claude, where
+// both the statusLine command and the hooks in hooks/hooks.json run it
+const BinaryName = "lunar-editor"
+
+// StatuslineCommand is the command Claude Code runs to render the statusline
+const StatuslineCommand = "~/.claude/" + BinaryName + " render"
+
+// GetBinaryPath returns the path the editor is installed at
+func GetBinaryPath() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(homeDir, ".claude", "statusline.sh"), nil
+	return filepath.Join(homeDir, ".claude", BinaryName), nil
 }
 
-// InstallStatuslineScript copies the statusline.sh script to ~/.claude/statusline.sh
-func InstallStatuslineScript() error {
-	// Get the executable's directory to find statusline.sh
+// installBinary copies the running binary to GetBinaryPath, unless it is
+// already running from there
+func installBinary() error {
 	execPath, err := os.Executable()
 	if err != nil {
 		return err
 	}
-	execDir := filepath.Dir(execPath)
+	target, err := GetBinaryPath()
+	if err != nil {
+		return err
+	}
+	if self, err := os.Stat(execPath); err == nil {
+		if installed, err := os.Stat(target); err == nil && os.SameFile(self, installed) {
+			return nil
+		}
+	}
 
-	// Try multiple locations for statusline.sh
-	possiblePaths := []string{
-		filepath.Join(execDir, "statusline.sh"),
-		filepath.Join(execDir, "..", "statusline.sh"),
-		filepath.Join(execDir, "..", "..", "statusline.sh"),
+	data, err := os.ReadFile(execPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
 	}
+	return writeAtomic(target, data, 0755)
+}
 
-	var scriptContent []byte
-	var found bool
-	for _, srcPath := range possiblePaths {
-		content, err := os.ReadFile(srcPath)
-		if err == nil {
-			scriptContent = content
-			found = true
-			break
-		}
+// InstallStatusline installs this binary in ~/.claude and points the
+// statusLine command in ~/.claude/settings.json at it
+func InstallStatusline() error {
+	if err := installBinary(); err != nil {
+		return err
 	}
 
-	if !found {
-		return os.ErrNotExist
+	settingsPath, err := GetSettingsPath()
+	if err != nil {
+		return err
 	}
 
-	destPath, err := GetStatuslineScriptPath()
+	// Keep every other setting intact
+	settings := map[string]json.RawMessage{}
+	data, err := os.ReadFile(settingsPath)
+	if err == nil {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	statusLine, err := json.Marshal(map[string]interface{}{
+		"type":    "command",
+		"command": StatuslineCommand,
+		"padding": 0,
+	})
 	if err != nil {
 		return err
 	}
+	settings["statusLine"] = statusLine
 
-	// Ensure ~/.claude directory exists
-	destDir := filepath.Dir(destPath)
-	if err := os.MkdirAll(destDir, 0755); err != nil {
+	out, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
 		return err
 	}
 
-	// Write the script with executable permissions
-	return os.WriteFile(destPath, scriptContent, 0755)
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		return err
+	}
+	return writeAtomic(settingsPath, append(out, '\n'), 0644)
 }
 
-// SaveAndInstall saves the config and installs the statusline script globally
+// SaveAndInstall saves the config and installs the statusline command globally
 func SaveAndInstall(cfg *Config) error {
 	// First save the config
 	if err := Save(cfg); err != nil {
 		return err
 	}
 
-	// Then install the script
-	return InstallStatuslineScript()
+	// Then point Claude Code at the renderer
+	return InstallStatusline()
 }
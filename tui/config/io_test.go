@@ -0,0 +1,45 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstallStatusline(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	settingsPath := filepath.Join(home, ".claude", "settings.json")
+	writeJSON(t, settingsPath, `{"model": "opus", "statusLine": {"type": "command", "command": "~/.claude/statusline.sh"}}`)
+
+	if err := InstallStatusline(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The statusline runs the binary the hooks run
+	info, err := os.Stat(filepath.Join(home, ".claude", BinaryName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("installed binary has mode %v, want it executable", info.Mode())
+	}
+
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings struct {
+		Model      string `json:"model"`
+		StatusLine struct {
+			Command string `json:"command"`
+		} `json:"statusLine"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings.StatusLine.Command != StatuslineCommand || settings.Model != "opus" {
+		t.Errorf("settings after install = %s, want the statusLine command %q and the model kept", data, StatuslineCommand)
+	}
+}
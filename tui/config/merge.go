@@ -0,0 +1,172 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// object is a JSON object that remembers the order of its keys
+type object struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+// decodeObject reads data as a JSON object, reporting false for anything else
+func decodeObject(data []byte) (object, bool) {
+	obj := object{values: map[string]json.RawMessage{}}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return obj, false
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return obj, false
+		}
+		key := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return obj, false
+		}
+		if _, seen := obj.values[key]; !seen {
+			obj.keys = append(obj.keys, key)
+		}
+		obj.values[key] = value
+	}
+	return obj, true
+}
+
+// encode writes the object compactly, keys in order
+func (o object) encode() []byte {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(o.values[key])
+	}
+	b.WriteByte('}')
+	return b.Bytes()
+}
+
+// jsonFields maps the JSON keys of a struct type to the types of their fields
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// decodeArray reads data as a JSON array, reporting false for anything else
+func decodeArray(data []byte) ([]json.RawMessage, bool) {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil || elems == nil {
+		return nil, false
+	}
+	return elems, true
+}
+
+// encodeArray writes the elements as a compact JSON array
+func encodeArray(elems []json.RawMessage) []byte {
+	var b bytes.Buffer
+	b.WriteByte('[')
+	for i, elem := range elems {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.Write(elem)
+	}
+	b.WriteByte(']')
+	return b.Bytes()
+}
+
+// mergeJSON lays updated, the marshalled value of type t, over orig. Keys of
+// orig that t has no field for are kept where they were, so settings read by
+// the script or added by newer versions survive a save. Fields t omits when
+// empty are removed, and new fields are added after the existing keys. Lists
+// are merged element by element, so objects in them keep their unknown keys
+// too, and maps keep the order of the keys they still have.
+func mergeJSON(orig, updated json.RawMessage, t reflect.Type) json.RawMessage {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var field func(key string) (reflect.Type, bool)
+	switch t.Kind() {
+	case reflect.Struct:
+		fields := jsonFields(t)
+		field = func(key string) (reflect.Type, bool) {
+			f, ok := fields[key]
+			return f, ok
+		}
+	case reflect.Map:
+		field = func(string) (reflect.Type, bool) { return t.Elem(), true }
+	case reflect.Slice, reflect.Array:
+		return mergeArray(orig, updated, t.Elem())
+	default:
+		return updated
+	}
+	o, ok := decodeObject(orig)
+	if !ok {
+		return updated
+	}
+	u, ok := decodeObject(updated)
+	if !ok {
+		return updated
+	}
+
+	merged := object{values: map[string]json.RawMessage{}}
+	for _, key := range o.keys {
+		value, present := u.values[key]
+		ft, known := field(key)
+		switch {
+		case present && known:
+			merged.values[key] = mergeJSON(o.values[key], value, ft)
+		case known:
+			continue // Cleared, and left out by omitempty
+		default:
+			merged.values[key] = o.values[key]
+		}
+		merged.keys = append(merged.keys, key)
+	}
+	for _, key := range u.keys {
+		if _, ok := merged.values[key]; !ok {
+			merged.keys = append(merged.keys, key)
+			merged.values[key] = u.values[key]
+		}
+	}
+	return merged.encode()
+}
+
+// mergeArray merges the lists orig and updated, whose elements are of type
+// elem, index by index. The list takes the length of updated.
+func mergeArray(orig, updated json.RawMessage, elem reflect.Type) json.RawMessage {
+	o, ok := decodeArray(orig)
+	if !ok {
+		return updated
+	}
+	u, ok := decodeArray(updated)
+	if !ok {
+		return updated
+	}
+	for i := range u {
+		if i < len(o) {
+			u[i] = mergeJSON(o[i], u[i], elem)
+		}
+	}
+	return encodeArray(u)
+}
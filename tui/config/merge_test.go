@@ -0,0 +1,92 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// roundTrip loads the round-trip fixture from a temp copy, applies edit and
+// saves it back, returning the saved file
+func roundTrip(t *testing.T, edit func(*Config)) []byte {
+	t.Helper()
+	orig, err := os.ReadFile(filepath.Join("testdata", "roundtrip", "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	if err := os.WriteFile(path, orig, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit(cfg)
+	if err := SaveToPath(cfg, path); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return saved
+}
+
+func TestSaveKeepsUnknownKeys(t *testing.T) {
+	got := roundTrip(t, func(cfg *Config) {
+		cfg.EnabledSections["mascot"] = true
+		cfg.EnabledSections["cost"] = true
+		cfg.Colors.Directory = "cyan"
+		cfg.Display.Lines[0].Sections = append(cfg.Display.Lines[0].Sections, "model")
+		cfg.Display.Lines[1].Separator = " | "
+		cfg.Display.Lines = append(cfg.Display.Lines, Line{Sections: []string{"cost"}})
+		cfg.Display.Priorities["git"] = 5
+	})
+
+	golden := filepath.Join("testdata", "roundtrip", "edited.golden.json")
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("saved\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSaveUnchangedIsStable(t *testing.T) {
+	first := roundTrip(t, func(*Config) {})
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	if err := os.WriteFile(path, first, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveToPath(cfg, path); err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := os.ReadFile(path); !bytes.Equal(first, second) {
+		t.Errorf("a second save changed the file:\n%s\nthen\n%s", first, second)
+	}
+}
+
+func TestSaveDropsRemovedLine(t *testing.T) {
+	got := roundTrip(t, func(cfg *Config) {
+		cfg.Display.Lines = cfg.Display.Lines[:1]
+	})
+	if bytes.Contains(got, []byte(`"align"`)) {
+		t.Errorf("the removed line's keys survived the save:\n%s", got)
+	}
+	if !bytes.Contains(got, []byte(`"name": "top"`)) {
+		t.Errorf("the kept line lost its unknown key:\n%s", got)
+	}
+}
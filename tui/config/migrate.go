@@ -0,0 +1,137 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CurrentVersion is the schema version written by this build
+const CurrentVersion = "2.0"
+
+// migration upgrades the raw JSON of a config file from one schema version to the next
+type migration struct {
+	From, To string
+	// Apply rewrites raw in place and returns a line describing each change
+	// it made; nil when the step only changes the version
+	Apply func(raw map[string]interface{}) []string
+}
+
+// migrations are applied in order, each starting where the previous one ended.
+// 1.0 files have the 2.0 shape, so upgrading them only stamps the version.
+var migrations = []migration{
+	{From: "1.0", To: "2.0"},
+}
+
+// Migration records a schema upgrade applied while loading a config file
+type Migration struct {
+	From, To string
+	Changes  []string
+	Backup   string // Where the file as it was is kept once the upgrade is saved
+}
+
+func (m Migration) String() string {
+	if len(m.Changes) == 0 {
+		return fmt.Sprintf("%s → %s", m.From, m.To)
+	}
+	return fmt.Sprintf("%s → %s: %s", m.From, m.To, strings.Join(m.Changes, "; "))
+}
+
+// fileVersion returns the schema version of a raw config; files from before
+// versioning count as 1.0
+func fileVersion(raw map[string]interface{}) string {
+	if v, ok := raw["version"].(string); ok && v != "" {
+		return v
+	}
+	return "1.0"
+}
+
+// Migrate upgrades the raw JSON of a config file to CurrentVersion. It returns
+// the upgraded JSON and the steps applied; files that are current, or newer
+// than this build knows, are returned unchanged.
+func Migrate(data []byte) ([]byte, []Migration, error) {
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, err
+	}
+
+	var applied []Migration
+	reshaped := false
+	for _, m := range migrations {
+		if fileVersion(raw) != m.From {
+			continue
+		}
+		var changes []string
+		if m.Apply != nil {
+			changes = m.Apply(raw)
+			reshaped = true
+		}
+		applied = append(applied, Migration{From: m.From, To: m.To, Changes: changes})
+		raw["version"] = m.To
+	}
+	if len(applied) == 0 {
+		return data, nil, nil
+	}
+	if !reshaped {
+		// Only the version changes: stamp it without reordering the file's keys
+		if out, ok := stampVersion(data, raw["version"]); ok {
+			return out, applied, nil
+		}
+	}
+
+	out, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, applied, nil
+}
+
+// stampVersion sets the version of the raw config object in data, keeping
+// its keys in order; a missing version goes first
+func stampVersion(data []byte, version interface{}) ([]byte, bool) {
+	obj, ok := decodeObject(data)
+	if !ok {
+		return nil, false
+	}
+	value, err := json.Marshal(version)
+	if err != nil {
+		return nil, false
+	}
+	if _, present := obj.values["version"]; !present {
+		obj.keys = append([]string{"version"}, obj.keys...)
+	}
+	obj.values["version"] = value
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, obj.encode(), "", "  "); err != nil {
+		return nil, false
+	}
+	return out.Bytes(), true
+}
+
+// MigrationBackup returns where the config at path is kept, as it was
+// before being upgraded from the given schema version
+func MigrationBackup(path, version string) string {
+	return path + ".v" + version + ".bak"
+}
+
+// backupBeforeMigration copies data, the config file at path, to its
+// MigrationBackup when it is from an older schema version. It is called when
+// the upgraded config is saved over it; an existing backup of the same
+// version is kept.
+func backupBeforeMigration(path string, data []byte) error {
+	_, applied, err := Migrate(data)
+	if err != nil || len(applied) == 0 {
+		return nil
+	}
+	backup := MigrationBackup(path, applied[0].From)
+	if _, err := os.Stat(backup); err == nil {
+		return nil
+	}
+	if err := writeAtomic(backup, data, 0644); err != nil {
+		return fmt.Errorf("backing up config before migration: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// migrateFixtures returns the old-version config fixtures, without their golden files
+func migrateFixtures(t *testing.T) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join("testdata", "migrate", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixtures []string
+	for _, path := range paths {
+		if !strings.HasSuffix(path, ".golden.json") {
+			fixtures = append(fixtures, path)
+		}
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no migration fixtures")
+	}
+	return fixtures
+}
+
+func TestMigrateGolden(t *testing.T) {
+	for _, path := range migrateFixtures(t) {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _, err := Migrate(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := strings.TrimSuffix(path, ".json") + ".golden.json"
+			if *update {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Migrate(%s) =\n%s\nwant\n%s", path, got, want)
+			}
+		})
+	}
+}
+
+func TestMigrateChanges(t *testing.T) {
+	tests := map[string][]string{
+		"v1.0_unversioned": {"1.0 → 2.0"},
+		"v1.0_lists":       {"1.0 → 2.0"},
+		"v2.0_current":     nil,
+		"v9.0_newer":       nil,
+	}
+	for name, want := range tests {
+		data, err := os.ReadFile(filepath.Join("testdata", "migrate", name+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, applied, err := Migrate(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, m := range applied {
+			got = append(got, m.String())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: migrations %q, want %q", name, got, want)
+		}
+	}
+}
+
+// copyFixture copies a migration fixture to a config file in a temp dir
+func copyFixture(t *testing.T, name string) (path string, orig []byte) {
+	t.Helper()
+	orig, err := os.ReadFile(filepath.Join("testdata", "migrate", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path = filepath.Join(t.TempDir(), ConfigFileName)
+	if err := os.WriteFile(path, orig, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path, orig
+}
+
+func TestLoadMigratesInMemory(t *testing.T) {
+	path, orig := copyFixture(t, "v1.0_unversioned")
+
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Migrations) != 1 || cfg.Migrations[0].Backup != MigrationBackup(path, "1.0") {
+		t.Errorf("Migrations = %+v, want one upgrade from 1.0 noting its backup", cfg.Migrations)
+	}
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %s, want %s", cfg.Version, CurrentVersion)
+	}
+	if want := []string{"🌑", "🌘", "🌗", "🌖", "🌕"}; !reflect.DeepEqual(cfg.Icons.Moons, want) {
+		t.Errorf("Icons.Moons = %q, want %q", cfg.Icons.Moons, want)
+	}
+
+	// Loading, as every render does, must leave the directory alone
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, orig) {
+		t.Error("LoadFromPath rewrote the config")
+	}
+	if _, err := os.Stat(MigrationBackup(path, "1.0")); !os.IsNotExist(err) {
+		t.Error("LoadFromPath wrote a migration backup")
+	}
+}
+
+func TestSaveKeepsMigrationBackup(t *testing.T) {
+	path, orig := copyFixture(t, "v1.0_unversioned")
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveToPath(cfg, path); err != nil {
+		t.Fatal(err)
+	}
+
+	backup := MigrationBackup(path, "1.0")
+	if data, err := os.ReadFile(backup); err != nil || !bytes.Equal(data, orig) {
+		t.Errorf("backup = %q, %v; want the original file", data, err)
+	}
+	saved, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Version != CurrentVersion || len(saved.Migrations) != 0 {
+		t.Errorf("saved file is version %s with migrations %v, want %s and none", saved.Version, saved.Migrations, CurrentVersion)
+	}
+
+	// Saving a current file leaves the backup as it was
+	if err := os.WriteFile(backup, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveToPath(saved, path); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(backup); string(data) != "{}" {
+		t.Error("saving a current config replaced the migration backup")
+	}
+}
@@ -0,0 +1,248 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// OverlayNames are the project overlay files looked for in each directory,
+// in order of preference
+var OverlayNames = []string{
+	filepath.Join(".claude", ConfigFileName),
+	".lunar.json",
+}
+
+// ProjectRoot returns the repository root containing dir, or dir itself
+// when it is not in a repository
+func ProjectRoot(dir string) string {
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
+
+// FindOverlay returns the nearest project overlay from dir up to its
+// repository root, or "" if there is none. The global config in the home
+// directory is never taken for an overlay.
+func FindOverlay(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	dir, _ = filepath.Abs(dir)
+	root := ProjectRoot(dir)
+	home, _ := os.UserHomeDir()
+	for d := dir; ; d = filepath.Dir(d) {
+		if d != home {
+			for _, name := range OverlayNames {
+				path := filepath.Join(d, name)
+				if info, err := os.Stat(path); err == nil && !info.IsDir() {
+					return path
+				}
+			}
+		}
+		if d == root || filepath.Dir(d) == d {
+			return ""
+		}
+	}
+}
+
+// isNull reports whether a raw JSON value is null
+func isNull(v json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(v), []byte("null"))
+}
+
+// mergeOverlay deep-merges the overlay JSON over base: objects are merged key
+// by key, anything else replaces the base value, and null removes it so the
+// default applies
+func mergeOverlay(base, overlay []byte) ([]byte, error) {
+	if base == nil {
+		base = []byte("{}")
+	}
+	if _, ok := decodeObject(overlay); !ok {
+		return nil, errors.New("a project overlay must be a JSON object")
+	}
+	return mergeLayer(base, overlay), nil
+}
+
+// mergeLayer merges one overlay value over a base value
+func mergeLayer(base, over json.RawMessage) json.RawMessage {
+	b, ok := decodeObject(base)
+	if !ok {
+		return over
+	}
+	o, ok := decodeObject(over)
+	if !ok {
+		return over
+	}
+
+	merged := object{values: map[string]json.RawMessage{}}
+	for _, key := range b.keys {
+		value, present := o.values[key]
+		switch {
+		case !present:
+			merged.values[key] = b.values[key]
+		case isNull(value):
+			continue
+		default:
+			merged.values[key] = mergeLayer(b.values[key], value)
+		}
+		merged.keys = append(merged.keys, key)
+	}
+	for _, key := range o.keys {
+		if _, seen := b.values[key]; !seen && !isNull(o.values[key]) {
+			merged.keys = append(merged.keys, key)
+			merged.values[key] = o.values[key]
+		}
+	}
+	return merged.encode()
+}
+
+// diffLayer returns the overlay that turns base into updated: the keys whose
+// values differ, with null for keys updated no longer has. It reports false
+// when the two are the same.
+func diffLayer(base, updated json.RawMessage) (json.RawMessage, bool) {
+	b, bok := decodeObject(base)
+	u, uok := decodeObject(updated)
+	if !bok || !uok {
+		var bv, uv interface{}
+		json.Unmarshal(base, &bv)
+		json.Unmarshal(updated, &uv)
+		return updated, !reflect.DeepEqual(bv, uv)
+	}
+
+	diff := object{values: map[string]json.RawMessage{}}
+	for _, key := range u.keys {
+		value := u.values[key]
+		if old, ok := b.values[key]; ok {
+			var changed bool
+			if value, changed = diffLayer(old, value); !changed {
+				continue
+			}
+		}
+		diff.keys = append(diff.keys, key)
+		diff.values[key] = value
+	}
+	for _, key := range b.keys {
+		if _, ok := u.values[key]; !ok {
+			diff.keys = append(diff.keys, key)
+			diff.values[key] = json.RawMessage("null")
+		}
+	}
+	return diff.encode(), len(diff.keys) > 0
+}
+
+// applyLayer writes a diffLayer change set into an overlay: objects are
+// updated key by key, anything else is replaced, and keys the changes do not
+// mention are kept as they are, explicit nulls included
+func applyLayer(overlay, changes json.RawMessage) json.RawMessage {
+	o, ok := decodeObject(overlay)
+	if !ok {
+		return changes
+	}
+	c, ok := decodeObject(changes)
+	if !ok {
+		return changes
+	}
+	for _, key := range c.keys {
+		if _, present := o.values[key]; !present {
+			o.keys = append(o.keys, key)
+		}
+		o.values[key] = applyLayer(o.values[key], c.values[key])
+	}
+	return o.encode()
+}
+
+// overlayFor returns the overlay file at path with the edits from loaded to
+// cfg written into it. Everything the file already sets is kept, even values
+// that match the global config and explicit nulls.
+func overlayFor(loaded, cfg *Config, path string) (object, error) {
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		data = []byte("{}")
+	case err != nil:
+		return object{}, err
+	default:
+		if data, _, err = Migrate(data); err != nil {
+			return object{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if _, ok := decodeObject(data); !ok {
+		return object{}, fmt.Errorf("%s: a project overlay must be a JSON object", path)
+	}
+
+	base, err := json.Marshal(loaded)
+	if err != nil {
+		return object{}, err
+	}
+	updated, err := json.Marshal(cfg)
+	if err != nil {
+		return object{}, err
+	}
+	if changes, changed := diffLayer(base, updated); changed {
+		data = applyLayer(data, changes)
+	}
+	obj, _ := decodeObject(data)
+	return obj, nil
+}
+
+// Overrides returns the JSON paths the overlay at path sets once the edits
+// from loaded to cfg are saved, e.g. "display.separator"; objects are listed
+// by their leaves
+func Overrides(loaded, cfg *Config, path string) map[string]bool {
+	paths := map[string]bool{}
+	obj, err := overlayFor(loaded, cfg, path)
+	if err != nil {
+		return paths
+	}
+	var walk func(prefix string, o object)
+	walk = func(prefix string, o object) {
+		for _, key := range o.keys {
+			if child, ok := decodeObject(o.values[key]); ok {
+				walk(prefix+key+".", child)
+			} else {
+				paths[prefix+key] = true
+			}
+		}
+	}
+	walk("", obj)
+	delete(paths, "version")
+	return paths
+}
+
+// SaveOverlay writes the edits from loaded to cfg into the project overlay at
+// path, leaving the rest of the file as it is so that everything it does not
+// set keeps following the global config. The overlay belongs to the project,
+// whose history keeps its earlier versions, so no backups are kept next to it.
+func SaveOverlay(loaded, cfg *Config, path string) error {
+	obj, err := overlayFor(loaded, cfg, path)
+	if err != nil {
+		return err
+	}
+	// Stamp the schema so the overlay is migrated correctly in future
+	version, _ := json.Marshal(CurrentVersion)
+	if _, ok := obj.values["version"]; !ok {
+		obj.keys = append([]string{"version"}, obj.keys...)
+	}
+	obj.values["version"] = version
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, obj.encode(), "", "  "); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeAtomic(path, out.Bytes(), 0644)
+}
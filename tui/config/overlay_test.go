@@ -0,0 +1,159 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeOverlay(t *testing.T) {
+	base := []byte(`{"display":{"separator":" | ","style":"plain"},"icons":{"moons":["a","b"]},"budget":{"session_usd":5}}`)
+	overlay := []byte(`{"display":{"style":"powerline"},"icons":{"moons":["c"]},"budget":null,"git":{"timeout_ms":100}}`)
+
+	got, err := mergeOverlay(base, overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"display":{"separator":" | ","style":"powerline"},"icons":{"moons":["c"]},"git":{"timeout_ms":100}}`
+	if string(got) != want {
+		t.Errorf("mergeOverlay =\n%s, want\n%s", got, want)
+	}
+
+	if _, err := mergeOverlay(base, []byte(`["not", "an", "object"]`)); err == nil {
+		t.Error("an overlay that is not an object was accepted")
+	}
+	if got, _ := mergeOverlay(nil, []byte(`{"version":"2.0"}`)); string(got) != `{"version":"2.0"}` {
+		t.Errorf("overlay without a global config = %s", got)
+	}
+}
+
+func TestLoadWithOverlay(t *testing.T) {
+	dir := t.TempDir()
+	global := filepath.Join(dir, "global.json")
+	overlay := filepath.Join(dir, "project", ".lunar.json")
+	writeJSON(t, global, `{"version":"2.0","display":{"separator":" • ","max_width":80},"budget":{"session_usd":5}}`)
+	writeJSON(t, overlay, `{"version":"2.0","display":{"max_width":120},"budget":{"session_usd":null}}`)
+
+	cfg, err := LoadWithOverlay(global, overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Overlay != overlay {
+		t.Errorf("Overlay = %q, want %q", cfg.Overlay, overlay)
+	}
+	if cfg.Display.Separator != " • " || cfg.Display.MaxWidth != 120 {
+		t.Errorf("separator %q and max width %d, want the global separator and the project width", cfg.Display.Separator, cfg.Display.MaxWidth)
+	}
+	if want := DefaultConfig().Budget.SessionUSD; cfg.Budget.SessionUSD != want {
+		t.Errorf("session budget %g, want the default %g after the overlay's null", cfg.Budget.SessionUSD, want)
+	}
+
+	missing, err := LoadWithOverlay(global, filepath.Join(dir, "none.json"))
+	if err != nil || missing.Overlay != "" || missing.Display.MaxWidth != 80 {
+		t.Errorf("with a missing overlay: %v, overlay %q, max width %d", err, missing.Overlay, missing.Display.MaxWidth)
+	}
+}
+
+func TestFindOverlay(t *testing.T) {
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	t.Setenv("HOME", home)
+	repo := filepath.Join(home, "repo")
+	sub := filepath.Join(repo, "pkg", "api")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// The global config is not an overlay, and nothing above the repository is looked at
+	writeJSON(t, filepath.Join(home, ".claude", ConfigFileName), `{}`)
+	writeJSON(t, filepath.Join(root, ".lunar.json"), `{}`)
+
+	if got := FindOverlay(sub); got != "" {
+		t.Errorf("without a project overlay: %q", got)
+	}
+
+	repoOverlay := filepath.Join(repo, ".claude", ConfigFileName)
+	writeJSON(t, repoOverlay, `{}`)
+	if got := FindOverlay(sub); got != repoOverlay {
+		t.Errorf("FindOverlay = %q, want the repository's %q", got, repoOverlay)
+	}
+
+	nearer := filepath.Join(repo, "pkg", ".lunar.json")
+	writeJSON(t, nearer, `{}`)
+	if got := FindOverlay(sub); got != nearer {
+		t.Errorf("FindOverlay = %q, want the nearer %q", got, nearer)
+	}
+}
+
+func TestSaveOverlay(t *testing.T) {
+	dir := t.TempDir()
+	global := filepath.Join(dir, "global.json")
+	path := filepath.Join(dir, "project", ".lunar.json")
+	writeJSON(t, global, `{"version":"2.0","display":{"separator":" | "},"colors":{"model":"cyan"}}`)
+	// The separator matches the global one and the null resets a value: both stay set here
+	writeJSON(t, path, `{"project_note":"kept","display":{"separator":" | ","cost_precision":null},"colors":{"model":"red"}}`)
+
+	cfg, err := LoadWithOverlay(global, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := cfg.Clone()
+	cfg.Colors.Model = "blue"
+	cfg.Budget.SessionUSD = 2.5
+	cfg.EnabledSections["git"] = false
+
+	wantPaths := map[string]bool{
+		"project_note": true, "display.separator": true, "display.cost_precision": true,
+		"colors.model": true, "budget.session_usd": true, "enabled_sections.git": true,
+	}
+	if got := Overrides(loaded, cfg, path); !reflect.DeepEqual(got, wantPaths) {
+		t.Errorf("Overrides = %v, want %v", got, wantPaths)
+	}
+
+	if err := SaveOverlay(loaded, cfg, path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved map[string]interface{}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"version":          CurrentVersion,
+		"project_note":     "kept",
+		"display":          map[string]interface{}{"separator": " | ", "cost_precision": nil},
+		"colors":           map[string]interface{}{"model": "blue"},
+		"budget":           map[string]interface{}{"session_usd": 2.5},
+		"enabled_sections": map[string]interface{}{"git": false},
+	}
+	if !reflect.DeepEqual(saved, want) {
+		t.Errorf("saved overlay =\n%s\nwant %v", data, want)
+	}
+
+	// The unversioned overlay keeps the order it was written in
+	var last int
+	for _, key := range []string{`"version"`, `"project_note"`, `"display"`, `"colors"`, `"budget"`} {
+		i := strings.Index(string(data), key)
+		if i < last {
+			t.Errorf("saved overlay =\n%s\nwant %s after the keys before it", data, key)
+		}
+		last = i
+	}
+
+	// The overlay lives in the project: nothing else is written there
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("project directory holds %d entries after saving, want only the overlay", len(entries))
+	}
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"statusline-config/color"
+)
+
+// FieldError is a problem with one config value, located by its dotted JSON path
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ErrorsAt returns the messages of the errors at path or below it
+func ErrorsAt(errs []FieldError, path string) []string {
+	var msgs []string
+	for _, e := range errs {
+		if e.Path == path || strings.HasPrefix(e.Path, path+".") || strings.HasPrefix(e.Path, path+"[") {
+			msgs = append(msgs, e.Message)
+		}
+	}
+	return msgs
+}
+
+// validator collects field errors
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) errorf(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) between(path string, value, lo, hi int) {
+	if value < lo || value > hi {
+		v.errorf(path, "must be between %d and %d, got %d", lo, hi, value)
+	}
+}
+
+func (v *validator) atLeast(path string, value, lo int) {
+	if value < lo {
+		v.errorf(path, "must be at least %d, got %d", lo, value)
+	}
+}
+
+func (v *validator) oneOf(path, value string, options []string) {
+	for _, opt := range options {
+		if value == opt {
+			return
+		}
+	}
+	v.errorf(path, "must be one of %s, got %q", strings.Join(options, ", "), value)
+}
+
+func (v *validator) notEmpty(path string, list []string, noun string) {
+	if len(list) == 0 {
+		v.errorf(path, "needs at least one %s", noun)
+	}
+	for i, s := range list {
+		if strings.TrimSpace(s) == "" {
+			v.errorf(fmt.Sprintf("%s[%d]", path, i), "must not be blank")
+		}
+	}
+}
+
+func (v *validator) animationSpeed(path string, speed int) {
+	if speed < 1 {
+		v.errorf(path, "animation speed must be at least 1 ms, got %d", speed)
+	}
+}
+
+// Validate checks the config for values the statusline cannot use, returning
+// one error per offending field
+func (c *Config) Validate() []FieldError {
+	v := &validator{}
+
+	t := c.Thresholds
+	v.atLeast("thresholds.directory_max_length", t.DirectoryMaxLength, 1)
+	v.atLeast("thresholds.directory_truncate_to", t.DirectoryTruncateTo, 1)
+	if t.DirectoryTruncateTo > t.DirectoryMaxLength {
+		v.errorf("thresholds.directory_truncate_to", "must not exceed directory_max_length (%d), got %d", t.DirectoryMaxLength, t.DirectoryTruncateTo)
+	}
+	v.atLeast("thresholds.token_k_format", t.TokenKFormat, 0)
+	if len(t.MoonPhases) == 0 {
+		v.errorf("thresholds.moon_phases", "needs at least one threshold")
+	} else if err := ValidateMoonPhases(t.MoonPhases); err != nil {
+		v.errorf("thresholds.moon_phases", "%v", err)
+	}
+
+	v.notEmpty("icons.moons", c.Icons.Moons, "icon")
+	if n := len(c.Icons.Moons); n > 0 && n < len(t.MoonPhases)+1 {
+		v.errorf("icons.moons", "has %d icons for %d moon phases; needs one more than thresholds.moon_phases", n, len(t.MoonPhases)+1)
+	}
+
+	// Colors: every string field must parse
+	colors := reflect.ValueOf(c.Colors)
+	for i := 0; i < colors.NumField(); i++ {
+		key := strings.Split(colors.Type().Field(i).Tag.Get("json"), ",")[0]
+		if _, err := color.Parse(colors.Field(i).String()); err != nil {
+			v.errorf("colors."+key, "%v", err)
+		}
+	}
+
+	moods := []struct {
+		key   string
+		state MascotState
+	}{
+		{"context_panic", c.Mascot.ContextPanic},
+		{"productive", c.Mascot.Productive},
+		{"deletion", c.Mascot.Deletion},
+	}
+	for _, mood := range moods {
+		key, state := mood.key, mood.state
+		if !state.Enabled {
+			continue
+		}
+		v.notEmpty("mascot."+key+".emojis", state.Emojis, "emoji")
+		if state.Animate {
+			// The script divides the clock by the speed to pick a frame
+			v.animationSpeed("mascot."+key+".speed", state.Speed)
+		}
+		v.atLeast("mascot."+key+".threshold", state.Threshold, 0)
+	}
+	if tb := c.Mascot.TimeBased; tb.Enabled {
+		v.notEmpty("mascot.time_based.night", tb.Night, "emoji")
+		v.notEmpty("mascot.time_based.morning", tb.Morning, "emoji")
+		v.notEmpty("mascot.time_based.afternoon", tb.Afternoon, "emoji")
+		v.notEmpty("mascot.time_based.evening", tb.Evening, "emoji")
+		if tb.Animate {
+			v.animationSpeed("mascot.time_based.speed", tb.Speed)
+		}
+	}
+
+	if c.Budget.SessionUSD < 0 {
+		v.errorf("budget.session_usd", "must not be negative, got %g", c.Budget.SessionUSD)
+	}
+	v.between("budget.warn_at", c.Budget.WarnAt, 0, 100)
+
+	v.atLeast("waiting_indicator.timeout", c.WaitingIndicator.Timeout, 1)
+
+	v.atLeast("git.cache_ttl", c.Git.CacheTTL, 0)
+	v.atLeast("git.timeout_ms", c.Git.TimeoutMs, 0)
+
+	d := c.Display
+	v.oneOf("display.style", d.Style, DisplayStyles())
+	v.oneOf("display.directory_style", d.DirectoryStyle, DirectoryStyles())
+	v.oneOf("display.gauge.style", d.Gauge.Style, GaugeStyles())
+	for _, style := range GaugeStyles() {
+		if width, ok := d.Gauge.Widths[style]; ok {
+			v.between("display.gauge.widths."+style, width, 1, 40)
+		}
+	}
+	v.between("display.cost_precision", d.CostPrecision, 0, 6)
+	v.atLeast("display.max_width", d.MaxWidth, 0)
+	for i, line := range d.Lines {
+		if len(line.Sections) == 0 {
+			v.errorf(fmt.Sprintf("display.lines[%d].sections", i), "needs at least one section")
+		}
+	}
+
+	n := c.Notifications
+	v.between("notifications.terminal_bell.context_threshold", n.TerminalBell.ContextThreshold, 0, 100)
+	v.between("notifications.desktop.context_threshold", n.Desktop.ContextThreshold, 0, 100)
+	v.between("notifications.blinking_text.context_threshold", n.BlinkingText.ContextThreshold, 0, 100)
+	v.between("notifications.terminal_title.context_threshold", n.TerminalTitle.ContextThreshold, 0, 100)
+	v.between("notifications.tmux.context_threshold", n.Tmux.ContextThreshold, 0, 100)
+	v.atLeast("notifications.terminal_bell.session_threshold", n.TerminalBell.SessionThreshold, 0)
+	v.atLeast("notifications.desktop.session_threshold", n.Desktop.SessionThreshold, 0)
+	v.atLeast("notifications.tmux.session_threshold", n.Tmux.SessionThreshold, 0)
+
+	return v.errs
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefaultConfigIsValid(t *testing.T) {
+	if errs := DefaultConfig().Validate(); len(errs) != 0 {
+		t.Errorf("DefaultConfig().Validate() = %v, want no errors", errs)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		edit  func(*Config)
+		paths []string
+	}{
+		{"truncate past max length", func(c *Config) {
+			c.Thresholds.DirectoryMaxLength, c.Thresholds.DirectoryTruncateTo = 10, 12
+		}, []string{"thresholds.directory_truncate_to"}},
+		{"decreasing moon phases", func(c *Config) {
+			c.Thresholds.MoonPhases = []int{40, 20, 60, 80}
+		}, []string{"thresholds.moon_phases"}},
+		{"too few moons", func(c *Config) {
+			c.Icons.Moons = []string{"🌑", "🌕"}
+		}, []string{"icons.moons"}},
+		{"blank moon", func(c *Config) {
+			c.Icons.Moons[2] = " "
+		}, []string{"icons.moons[2]"}},
+		{"unknown color", func(c *Config) {
+			c.Colors.Model = "not-a-color"
+		}, []string{"colors.model"}},
+		{"enabled mood without emojis", func(c *Config) {
+			c.Mascot.Productive.Emojis = nil
+		}, []string{"mascot.productive.emojis"}},
+		{"disabled mood without emojis", func(c *Config) {
+			c.Mascot.Productive.Enabled = false
+			c.Mascot.Productive.Emojis = nil
+		}, nil},
+		{"zero animation speed", func(c *Config) {
+			c.Mascot.TimeBased.Speed = 0
+		}, []string{"mascot.time_based.speed"}},
+		{"negative budget", func(c *Config) {
+			c.Budget.SessionUSD = -1
+			c.Budget.WarnAt = 120
+		}, []string{"budget.session_usd", "budget.warn_at"}},
+		{"zero waiting timeout", func(c *Config) {
+			c.WaitingIndicator.Timeout = 0
+		}, []string{"waiting_indicator.timeout"}},
+		{"unknown display styles", func(c *Config) {
+			c.Display.Style = "fancy"
+			c.Display.Gauge.Style = "dots"
+		}, []string{"display.style", "display.gauge.style"}},
+		{"gauge too wide", func(c *Config) {
+			c.Display.Gauge.Widths[GaugeBlocks] = 41
+		}, []string{"display.gauge.widths.blocks"}},
+		{"empty line", func(c *Config) {
+			c.Display.Lines = []Line{{Sections: []string{"model"}}, {}}
+		}, []string{"display.lines[1].sections"}},
+		{"threshold over 100", func(c *Config) {
+			c.Notifications.Tmux.ContextThreshold = 101
+		}, []string{"notifications.tmux.context_threshold"}},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		tt.edit(cfg)
+		var paths []string
+		for _, err := range cfg.Validate() {
+			paths = append(paths, err.Path)
+		}
+		if !reflect.DeepEqual(paths, tt.paths) {
+			t.Errorf("%s: errors at %q, want %q", tt.name, paths, tt.paths)
+		}
+	}
+}
+
+func TestErrorsAt(t *testing.T) {
+	errs := []FieldError{
+		{Path: "icons.moons", Message: "too few"},
+		{Path: "icons.moons[1]", Message: "blank"},
+		{Path: "icons.moons_extra", Message: "other field"},
+		{Path: "mascot.productive.speed", Message: "slow"},
+		{Path: "mascot.productive.emojis", Message: "empty"},
+	}
+	tests := map[string][]string{
+		"icons.moons":          {"too few", "blank"},
+		"mascot.productive":    {"slow", "empty"},
+		"mascot":               {"slow", "empty"},
+		"mascot.context_panic": nil,
+	}
+	for path, want := range tests {
+		if got := ErrorsAt(errs, path); !reflect.DeepEqual(got, want) {
+			t.Errorf("ErrorsAt(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package git
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CacheDirName is the directory under ~/.claude holding one status file per repository
+const CacheDirName = ".statusline-git-cache.d"
+
+// Cache defaults
+const (
+	DefaultTTL     = 5 * time.Second
+	DefaultTimeout = 500 * time.Millisecond
+)
+
+// refreshStale is how long a background refresh may run before another one is started
+const refreshStale = time.Minute
+
+// Cache keeps the last status of each repository on disk, so renders can skip
+// git while nothing has changed and fall back to the last known status when
+// git is too slow
+type Cache struct {
+	Dir string
+	// TTL is how long a status is reused while .git/index and .git/HEAD are unchanged
+	TTL time.Duration
+	// Timeout is the hard deadline for git; zero waits as long as git takes
+	Timeout time.Duration
+	// Refresh, if set, is called when git misses the deadline so the status
+	// can be brought up to date in the background (see Update)
+	Refresh func(dir string)
+}
+
+// cacheEntry is the on-disk form of a cached status
+type cacheEntry struct {
+	Status     Status `json:"status"`
+	IndexMtime int64  `json:"index_mtime"`
+	HeadMtime  int64  `json:"head_mtime"`
+	Updated    int64  `json:"updated"`
+}
+
+// DefaultCache returns the cache in ~/.claude with the default TTL and timeout
+func DefaultCache() (*Cache, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{
+		Dir:     filepath.Join(homeDir, ".claude", CacheDirName),
+		TTL:     DefaultTTL,
+		Timeout: DefaultTimeout,
+	}, nil
+}
+
+// Status returns the status of the repository containing dir, from the cache
+// when it is still fresh. If git misses the deadline, the last known status is
+// returned marked as stale, or the timeout error when there is none.
+func (c *Cache) Status(dir string) (*Status, error) {
+	gitDir := FindGitDir(dir)
+	if gitDir == "" {
+		return nil, ErrNotRepository
+	}
+	path := c.path(gitDir)
+	index, head := mtime(filepath.Join(gitDir, "index")), mtime(filepath.Join(gitDir, "HEAD"))
+
+	cached := load(path)
+	if cached != nil && cached.IndexMtime == index && cached.HeadMtime == head &&
+		time.Since(time.Unix(0, cached.Updated)) < c.TTL {
+		return &cached.Status, nil
+	}
+
+	ctx := context.Background()
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	status, err := read(ctx, dir, gitDir)
+	if err == nil {
+		c.save(path, &cacheEntry{Status: *status, IndexMtime: index, HeadMtime: head, Updated: time.Now().UnixNano()})
+		return status, nil
+	}
+	if ctx.Err() == nil {
+		return nil, err
+	}
+
+	c.startRefresh(path, dir)
+	if cached == nil {
+		return nil, ctx.Err()
+	}
+	stale := cached.Status
+	stale.Stale = true
+	return &stale, nil
+}
+
+// Update reads the status of the repository containing dir without a
+// deadline and stores it in the cache. It is what a background refresh runs.
+func (c *Cache) Update(dir string) error {
+	gitDir := FindGitDir(dir)
+	if gitDir == "" {
+		return ErrNotRepository
+	}
+	path := c.path(gitDir)
+	defer os.Remove(path + ".refresh")
+
+	index, head := mtime(filepath.Join(gitDir, "index")), mtime(filepath.Join(gitDir, "HEAD"))
+	status, err := read(context.Background(), dir, gitDir)
+	if err != nil {
+		return err
+	}
+	return c.save(path, &cacheEntry{Status: *status, IndexMtime: index, HeadMtime: head, Updated: time.Now().UnixNano()})
+}
+
+// startRefresh calls Refresh unless a refresh of the same repository is already running
+func (c *Cache) startRefresh(path, dir string) {
+	if c.Refresh == nil {
+		return
+	}
+	marker := path + ".refresh"
+	if info, err := os.Stat(marker); err == nil && time.Since(info.ModTime()) < refreshStale {
+		return
+	}
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return
+	}
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		return
+	}
+	c.Refresh(dir)
+}
+
+// path returns the cache file for a repository, named after its git directory
+func (c *Cache) path(gitDir string) string {
+	sum := sha1.Sum([]byte(gitDir))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+func (c *Cache) save(path string, entry *cacheEntry) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.Dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// load returns the cached entry, or nil if there is none or it is unreadable
+func load(path string) *cacheEntry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	entry := &cacheEntry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil
+	}
+	return entry
+}
+
+// mtime returns the modification time of a file in nanoseconds, or 0 if it is missing
+func mtime(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.ModTime().UnixNano()
+}
@@ -0,0 +1,128 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// initRepo creates an empty repository, skipping the test without git
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", "-b", "main", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	return dir
+}
+
+func writeFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCacheReusesFreshStatus(t *testing.T) {
+	repo := initRepo(t)
+	c := &Cache{Dir: t.TempDir(), TTL: time.Hour}
+
+	if s, err := c.Status(repo); err != nil || s.Untracked != 0 {
+		t.Fatalf("Status = %+v, %v", s, err)
+	}
+	// A new untracked file touches neither the index nor HEAD
+	writeFile(t, filepath.Join(repo, "new.txt"))
+	if s, _ := c.Status(repo); s.Untracked != 0 {
+		t.Errorf("within the TTL: %d untracked, want the cached 0", s.Untracked)
+	}
+
+	c.TTL = 0
+	if s, _ := c.Status(repo); s.Untracked != 1 {
+		t.Errorf("after the TTL: %d untracked, want 1", s.Untracked)
+	}
+}
+
+func TestCacheSeesIndexChanges(t *testing.T) {
+	repo := initRepo(t)
+	c := &Cache{Dir: t.TempDir(), TTL: time.Hour}
+	writeFile(t, filepath.Join(repo, "new.txt"))
+	if _, err := c.Status(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("git", "add", "new.txt")
+	cmd.Dir = repo
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, out)
+	}
+	if s, _ := c.Status(repo); s.Staged != 1 || s.Untracked != 0 {
+		t.Errorf("after git add: %d staged, %d untracked, want 1 and 0", s.Staged, s.Untracked)
+	}
+}
+
+// slowGit puts a git on PATH that never answers in time
+func slowGit(t *testing.T) {
+	t.Helper()
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "git"), []byte("#!/bin/sh\nexec sleep 5\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestCacheTimeout(t *testing.T) {
+	repo := initRepo(t)
+	writeFile(t, filepath.Join(repo, "new.txt"))
+	c := &Cache{Dir: t.TempDir(), Timeout: 200 * time.Millisecond}
+	if _, err := c.Status(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	slowGit(t)
+	var refreshes []string
+	c.Refresh = func(dir string) { refreshes = append(refreshes, dir) }
+
+	start := time.Now()
+	s, err := c.Status(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Status took %v with a %v timeout", elapsed, c.Timeout)
+	}
+	if !s.Stale || s.Untracked != 1 {
+		t.Errorf("Status = %+v, want the last known status marked stale", *s)
+	}
+
+	// A refresh already running is not started again
+	if _, err := c.Status(repo); err != nil {
+		t.Fatal(err)
+	}
+	if len(refreshes) != 1 || refreshes[0] != repo {
+		t.Errorf("refreshes = %q, want one of %s", refreshes, repo)
+	}
+
+	// With nothing cached there is nothing to fall back to
+	empty := &Cache{Dir: t.TempDir(), Timeout: 200 * time.Millisecond}
+	if _, err := empty.Status(repo); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("uncached Status error = %v, want the deadline", err)
+	}
+}
+
+func TestCacheOutsideRepository(t *testing.T) {
+	dir := t.TempDir()
+	if FindGitDir(dir) != "" {
+		t.Skip("temp dir is inside a repository")
+	}
+	c := &Cache{Dir: t.TempDir()}
+	if _, err := c.Status(dir); err != ErrNotRepository {
+		t.Errorf("Status error = %v, want ErrNotRepository", err)
+	}
+}
@@ -0,0 +1,206 @@
+// Package git reads the state of a working tree for the statusline from a
+// single `git status --porcelain=v2` call.
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Operations that can be in progress in a repository
+const (
+	OpRebase     = "rebase"
+	OpMerge      = "merge"
+	OpCherryPick = "cherry-pick"
+	OpRevert     = "revert"
+	OpBisect     = "bisect"
+)
+
+// Status is a snapshot of a working tree
+type Status struct {
+	Branch    string // empty on a detached HEAD
+	Commit    string // full SHA of HEAD, empty before the first commit
+	Upstream  string
+	Ahead     int
+	Behind    int
+	Staged    int
+	Modified  int
+	Untracked int
+	Conflicts int
+	Stash     int
+	Operation string // one of the Op* constants, or empty
+	// Stale is set when git missed its deadline and this is the last known status
+	Stale bool `json:"-"`
+}
+
+// Detached reports whether HEAD points at a commit rather than a branch
+func (s *Status) Detached() bool {
+	return s.Branch == "" && s.Commit != ""
+}
+
+// Dirty reports whether the tree has any staged, unstaged, untracked or conflicted files
+func (s *Status) Dirty() bool {
+	return s.Staged+s.Modified+s.Untracked+s.Conflicts > 0
+}
+
+// ShortCommit returns the abbreviated SHA of HEAD
+func (s *Status) ShortCommit() string {
+	if len(s.Commit) > 7 {
+		return s.Commit[:7]
+	}
+	return s.Commit
+}
+
+// ErrNotRepository is returned for directories outside any git repository
+var ErrNotRepository = errors.New("not a git repository")
+
+// Read returns the status of the repository containing dir
+func Read(dir string) (*Status, error) {
+	gitDir := FindGitDir(dir)
+	if gitDir == "" {
+		return nil, ErrNotRepository
+	}
+	return read(context.Background(), dir, gitDir)
+}
+
+// read runs git status in dir, giving up when ctx is done
+func read(ctx context.Context, dir, gitDir string) (*Status, error) {
+	out, err := statusOutput(ctx, dir, "--show-stash")
+	if err != nil && ctx.Err() == nil {
+		// A git too old to know --show-stash rejects it: read the status without the stash count
+		out, err = statusOutput(ctx, dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	status := Parse(out)
+	status.Operation = operation(gitDir)
+	return status, nil
+}
+
+func statusOutput(ctx context.Context, dir string, extra ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"status", "--porcelain=v2", "--branch"}, extra...)...)
+	cmd.Dir = dir
+	// Don't let a status refresh rewrite the index, which would also invalidate the cache
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
+	// Stop waiting on the output of anything git spawned once git is killed
+	cmd.WaitDelay = 50 * time.Millisecond
+	return cmd.Output()
+}
+
+// Parse reads the output of `git status --porcelain=v2 --branch --show-stash`
+func Parse(out []byte) *Status {
+	s := &Status{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) < 2 {
+			continue
+		}
+		switch line[0] {
+		case '#':
+			s.parseHeader(strings.Fields(line[2:]))
+		case '1', '2':
+			// "1 XY ..." and "2 XY ..." for changed and renamed entries
+			if len(line) >= 4 {
+				if line[2] != '.' {
+					s.Staged++
+				}
+				if line[3] != '.' {
+					s.Modified++
+				}
+			}
+		case 'u':
+			s.Conflicts++
+		case '?':
+			s.Untracked++
+		}
+	}
+	return s
+}
+
+func (s *Status) parseHeader(fields []string) {
+	if len(fields) < 2 {
+		return
+	}
+	switch fields[0] {
+	case "branch.oid":
+		if fields[1] != "(initial)" {
+			s.Commit = fields[1]
+		}
+	case "branch.head":
+		if fields[1] != "(detached)" {
+			s.Branch = fields[1]
+		}
+	case "branch.upstream":
+		s.Upstream = fields[1]
+	case "branch.ab":
+		if len(fields) == 3 {
+			s.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "+"))
+			s.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "-"))
+		}
+	case "stash":
+		s.Stash, _ = strconv.Atoi(fields[1])
+	}
+}
+
+// FindGitDir walks up from dir to the repository's git directory, following
+// the "gitdir:" pointer used by worktrees and submodules. It returns "" when
+// dir is not inside a repository.
+func FindGitDir(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ".git")
+		if info, err := os.Stat(path); err == nil {
+			if info.IsDir() {
+				return path
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return ""
+			}
+			target := strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir:"))
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(dir, target)
+			}
+			return target
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// operation detects a rebase, merge, cherry-pick, revert or bisect in progress
+func operation(gitDir string) string {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(gitDir, name))
+		return err == nil
+	}
+	switch {
+	case exists("rebase-merge"), exists("rebase-apply"):
+		return OpRebase
+	case exists("MERGE_HEAD"):
+		return OpMerge
+	case exists("CHERRY_PICK_HEAD"):
+		return OpCherryPick
+	case exists("REVERT_HEAD"):
+		return OpRevert
+	case exists("BISECT_LOG"):
+		return OpBisect
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	out := `# branch.oid 4f2a9c1e0b7d5a3c2b1a09f8e7d6c5b4a3928170
+# branch.head feature/login
+# branch.upstream origin/feature/login
+# branch.ab +2 -3
+# stash 4
+1 M. N... 100644 100644 100644 aaaa bbbb staged.go
+1 .M N... 100644 100644 100644 aaaa bbbb modified.go
+1 MM N... 100644 100644 100644 aaaa bbbb both.go
+2 R. N... 100644 100644 100644 aaaa bbbb R100 new.go	old.go
+u UU N... 100644 100644 100644 100644 aaaa bbbb cccc conflict.go
+? notes.txt
+? tmp/
+`
+	got := Parse([]byte(out))
+	want := Status{
+		Branch:    "feature/login",
+		Commit:    "4f2a9c1e0b7d5a3c2b1a09f8e7d6c5b4a3928170",
+		Upstream:  "origin/feature/login",
+		Ahead:     2,
+		Behind:    3,
+		Staged:    3,
+		Modified:  2,
+		Untracked: 2,
+		Conflicts: 1,
+		Stash:     4,
+	}
+	if *got != want {
+		t.Errorf("Parse =\n%+v, want\n%+v", *got, want)
+	}
+	if got.Detached() || !got.Dirty() || got.ShortCommit() != "4f2a9c1" {
+		t.Errorf("Detached %v, Dirty %v, ShortCommit %q", got.Detached(), got.Dirty(), got.ShortCommit())
+	}
+}
+
+func TestParseDetachedAndInitial(t *testing.T) {
+	detached := Parse([]byte("# branch.oid 4f2a9c1e\n# branch.head (detached)\n"))
+	if !detached.Detached() || detached.Branch != "" || detached.Dirty() {
+		t.Errorf("detached HEAD parsed as %+v", *detached)
+	}
+
+	initial := Parse([]byte("# branch.oid (initial)\n# branch.head main\n"))
+	if initial.Commit != "" || initial.Branch != "main" || initial.Detached() {
+		t.Errorf("repository without commits parsed as %+v", *initial)
+	}
+}
+
+func TestFindGitDir(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	sub := filepath.Join(repo, "a", "b")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A worktree points at its git directory with a relative path
+	worktree := filepath.Join(root, "wt")
+	if err := os.MkdirAll(worktree, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: ../repo/.git/worktrees/wt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		repo:     filepath.Join(repo, ".git"),
+		sub:      filepath.Join(repo, ".git"),
+		worktree: filepath.Join(repo, ".git", "worktrees", "wt"),
+	}
+	if FindGitDir(filepath.Dir(root)) == "" {
+		tests[root] = ""
+	}
+	for dir, want := range tests {
+		if got := FindGitDir(dir); got != want {
+			t.Errorf("FindGitDir(%s) = %q, want %q", dir, got, want)
+		}
+	}
+}
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		marker string
+		want   string
+	}{
+		{"", ""},
+		{"rebase-merge", OpRebase},
+		{"rebase-apply", OpRebase},
+		{"MERGE_HEAD", OpMerge},
+		{"CHERRY_PICK_HEAD", OpCherryPick},
+		{"REVERT_HEAD", OpRevert},
+		{"BISECT_LOG", OpBisect},
+	}
+	for _, tt := range tests {
+		gitDir := t.TempDir()
+		if tt.marker != "" {
+			if err := os.WriteFile(filepath.Join(gitDir, tt.marker), nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := operation(gitDir); got != tt.want {
+			t.Errorf("with %q: operation = %q, want %q", tt.marker, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Package hook handles the Claude Code hook events that drive the waiting
+// indicator: it records per-session waiting state and sends notifications.
+package hook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"statusline-config/config"
+	"statusline-config/notify"
+	"statusline-config/state"
+)
+
+// Hook event names
+const (
+	EventNotification      = "Notification"
+	EventPermissionRequest = "PermissionRequest"
+	EventUserPromptSubmit  = "UserPromptSubmit"
+	EventPostToolUse       = "PostToolUse"
+	EventSessionStart      = "SessionStart"
+)
+
+// defaultMessage is shown when the hook input carries no message
+const defaultMessage = "Claude needs your input"
+
+// Input is the JSON document Claude Code passes to hooks on stdin
+type Input struct {
+	SessionID        string `json:"session_id"`
+	TranscriptPath   string `json:"transcript_path"`
+	Cwd              string `json:"cwd"`
+	HookEventName    string `json:"hook_event_name"`
+	NotificationType string `json:"notification_type"`
+	Message          string `json:"message"`
+	ToolName         string `json:"tool_name"`
+}
+
+// ReadInput parses the hook input from r
+func ReadInput(r io.Reader) (*Input, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	in := &Input{}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return in, nil
+	}
+	if err := json.Unmarshal(data, in); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+// Handler applies hook events to the state store
+type Handler struct {
+	Config *config.Config
+	Store  *state.Store
+	Env    notify.Env
+	Now    func() time.Time
+}
+
+// Handle processes a single hook event. The event argument wins over the
+// hook_event_name in the input so one binary can serve every hook entry.
+func (h *Handler) Handle(event string, in *Input) error {
+	if event == "" {
+		event = in.HookEventName
+	}
+	switch event {
+	case EventNotification, EventPermissionRequest:
+		return h.setWaiting(in)
+	case EventUserPromptSubmit, EventPostToolUse:
+		return h.clearWaiting(in)
+	case EventSessionStart:
+		// A new, resumed or cleared session starts from a clean slate
+		return h.Store.Delete(in.SessionID)
+	}
+	return fmt.Errorf("unsupported hook event %q", event)
+}
+
+// classify returns the waiting type and notification title for the input
+func classify(in *Input) (waitType, title string) {
+	switch {
+	case in.ToolName != "":
+		return "permission:" + in.ToolName, "Permission Required"
+	case in.NotificationType == "permission_prompt":
+		return "permission", "Permission Required"
+	case in.NotificationType == "elicitation_dialog":
+		return "question", "Claude has a question"
+	case in.NotificationType == "idle_prompt":
+		return "idle", "Claude is waiting"
+	}
+	return "input", "Input Required"
+}
+
+func (h *Handler) setWaiting(in *Input) error {
+	waitType, title := classify(in)
+	message := in.Message
+	if message == "" {
+		message = defaultMessage
+	}
+
+	var alreadyWaiting bool
+	err := h.Store.Update(in.SessionID, func(e *state.Entry) error {
+		alreadyWaiting = e.Waiting
+		e.Waiting = true
+		e.Type = waitType
+		e.Timestamp = h.Now().Unix()
+		e.Message = message
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only notify if this is a new waiting state
+	if alreadyWaiting {
+		return nil
+	}
+	channels := notify.ForWaiting(&h.Config.Notifications, h.Env)
+	return notify.Dispatch(channels, notify.Event{Title: title, Message: message})
+}
+
+func (h *Handler) clearWaiting(in *Input) error {
+	// PostToolUse fires on every tool call: only take the lock and write when
+	// there is a wait to clear. The renderer expires stale waits by flipping
+	// only Waiting, so check the timestamp too.
+	entry, err := h.Store.Get(in.SessionID)
+	if err != nil {
+		return err
+	}
+	if !entry.Waiting && entry.Timestamp == 0 {
+		return nil
+	}
+
+	var wasWaiting bool
+	err = h.Store.Update(in.SessionID, func(e *state.Entry) error {
+		wasWaiting = e.Waiting || e.Timestamp != 0
+		e.Waiting = false
+		e.Type = ""
+		e.Timestamp = 0
+		e.Message = ""
+		return nil
+	})
+	if err != nil || !wasWaiting {
+		return err
+	}
+
+	// Undo alerts that outlive the notification, like the tmux window style
+	return notify.Clear(notify.ForWaiting(&h.Config.Notifications, h.Env))
+}
@@ -0,0 +1,202 @@
+package hook
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"statusline-config/config"
+	"statusline-config/notify"
+	"statusline-config/state"
+)
+
+// fakeRunner records the commands channels would start, with only
+// notify-send installed
+type fakeRunner struct {
+	started []string
+}
+
+func (r *fakeRunner) Run(name string, args ...string) ([]byte, error) {
+	r.started = append(r.started, name+" "+strings.Join(args, " "))
+	return nil, nil
+}
+
+func (r *fakeRunner) Start(name string, args ...string) error {
+	r.started = append(r.started, name+" "+strings.Join(args, " "))
+	return nil
+}
+
+func (r *fakeRunner) LookPath(name string) bool { return name == "notify-send" }
+
+// readFixture parses a captured hook payload from testdata
+func readFixture(t *testing.T, name string) *Input {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	in, err := ReadInput(f)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return in
+}
+
+func TestHandle(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	waiting := &state.Entry{Waiting: true, Type: "permission", Timestamp: now.Add(-time.Minute).Unix(), Message: "earlier"}
+
+	tests := []struct {
+		name    string
+		fixture string
+		event   string       // Passed on the command line; "" uses hook_event_name
+		before  *state.Entry // Session state before the hook runs
+		fresh   bool         // The state directory does not exist yet
+		wantErr bool
+		want    state.Entry
+		notify  []string // Commands started to notify
+		bell    bool
+	}{
+		{
+			name:    "permission prompt",
+			fixture: "notification_permission.json",
+			want:    state.Entry{Waiting: true, Type: "permission", Timestamp: now.Unix(), Message: "Claude needs your permission to use Bash"},
+			notify:  []string{"notify-send -u critical Permission Required Claude needs your permission to use Bash"},
+			bell:    true,
+		},
+		{
+			name:    "question with quotes and newline",
+			fixture: "notification_question.json",
+			want:    state.Entry{Waiting: true, Type: "question", Timestamp: now.Unix(), Message: "Which \"database\" should I use?\nPostgres or SQLite"},
+			notify:  []string{"notify-send -u critical Claude has a question Which \"database\" should I use?\nPostgres or SQLite"},
+			bell:    true,
+		},
+		{
+			name:    "idle prompt",
+			fixture: "notification_idle.json",
+			event:   EventNotification,
+			want:    state.Entry{Waiting: true, Type: "idle", Timestamp: now.Unix(), Message: "Claude is waiting for your input"},
+			notify:  []string{"notify-send -u critical Claude is waiting Claude is waiting for your input"},
+			bell:    true,
+		},
+		{
+			name:    "permission request without message",
+			fixture: "permission_request.json",
+			want:    state.Entry{Waiting: true, Type: "permission:Bash", Timestamp: now.Unix(), Message: defaultMessage},
+			notify:  []string{"notify-send -u critical Permission Required " + defaultMessage},
+			bell:    true,
+		},
+		{
+			name:    "already waiting does not notify again",
+			fixture: "notification_permission.json",
+			before:  waiting,
+			want:    state.Entry{Waiting: true, Type: "permission", Timestamp: now.Unix(), Message: "Claude needs your permission to use Bash"},
+		},
+		{
+			name:    "post tool use clears",
+			fixture: "post_tool_use.json",
+			before:  waiting,
+			want:    state.Entry{},
+		},
+		{
+			name:    "user prompt submit clears",
+			fixture: "user_prompt_submit.json",
+			before:  waiting,
+			want:    state.Entry{},
+		},
+		{
+			name:    "session start forgets the session",
+			fixture: "session_start.json",
+			before:  &state.Entry{Waiting: true, Alerts: map[string]bool{"context:desktop": true}},
+			want:    state.Entry{},
+		},
+		{
+			name:    "session start on a fresh install",
+			fixture: "session_start.json",
+			fresh:   true,
+			want:    state.Entry{},
+		},
+		{
+			name:    "post tool use on a fresh install",
+			fixture: "post_tool_use.json",
+			fresh:   true,
+			want:    state.Entry{},
+		},
+		{
+			name:    "stop is not handled",
+			fixture: "stop.json",
+			before:  waiting,
+			wantErr: true,
+			want:    *waiting,
+		},
+		{
+			name:    "unknown event",
+			fixture: "notification_permission.json",
+			event:   "PreCompact",
+			wantErr: true,
+			want:    state.Entry{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := readFixture(t, tt.fixture)
+			cfg := config.DefaultConfig()
+			cfg.Notifications.TerminalBell.Enabled = true
+			cfg.Notifications.Desktop.Enabled = true
+			cfg.Notifications.Desktop.Sound = false
+			// Only context alerts are headed by the configured title
+			cfg.Notifications.Desktop.Title = "Context over 70%"
+			runner := &fakeRunner{}
+			var tty bytes.Buffer
+			dir := t.TempDir()
+			if tt.fresh {
+				dir = filepath.Join(dir, ".statusline-state.d")
+			}
+			h := &Handler{
+				Config: cfg,
+				Store:  &state.Store{Dir: dir},
+				Env:    notify.Env{Runner: runner, TTY: &tty},
+				Now:    func() time.Time { return now },
+			}
+			if tt.before != nil {
+				if err := h.Store.Update(in.SessionID, func(e *state.Entry) error { *e = *tt.before; return nil }); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := h.Handle(tt.event, in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Handle() error = %v, want error %v", err, tt.wantErr)
+			}
+			got, err := h.Store.Get(in.SessionID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("state = %+v, want %+v", *got, tt.want)
+			}
+			if !reflect.DeepEqual(runner.started, tt.notify) {
+				t.Errorf("started %q, want %q", runner.started, tt.notify)
+			}
+			if rang := tty.String() == "\a"; rang != tt.bell {
+				t.Errorf("bell rang = %v, want %v", rang, tt.bell)
+			}
+		})
+	}
+}
+
+func TestReadInputEmpty(t *testing.T) {
+	in, err := ReadInput(strings.NewReader("  \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *in != (Input{}) {
+		t.Errorf("ReadInput(blank) = %+v, want an empty input", *in)
+	}
+}
@@ -3,23 +3,87 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"statusline-config/config"
+	"statusline-config/git"
+	"statusline-config/hook"
+	"statusline-config/notify"
+	"statusline-config/payload"
+	"statusline-config/render"
+	"statusline-config/state"
 	"statusline-config/ui"
 )
 
 func main() {
-	// Load config
-	cfg, err := config.Load()
+	// Subcommands invoked by Claude Code
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "render":
+			runRender()
+			return
+		case "hook":
+			var event string
+			if len(os.Args) > 2 {
+				event = os.Args[2]
+			}
+			runHook(event)
+			return
+		case "git-refresh":
+			if len(os.Args) > 2 {
+				runGitRefresh(os.Args[2])
+			}
+			return
+		case "lint":
+			var path string
+			if len(os.Args) > 2 {
+				path = os.Args[2]
+			}
+			runLint(path)
+			return
+		}
+	}
+
+	// Load config: the global file, or with --project the overlay over it
+	path, err := config.GetConfigPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding config: %v\n", err)
+		os.Exit(1)
+	}
+	cfg, err := config.LoadFromPath(path)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
-
-	// Create and run the TUI
 	model := ui.NewModel(cfg)
+
+	if len(os.Args) > 1 && os.Args[1] == "--project" {
+		dir, _ := os.Getwd()
+		if len(os.Args) > 2 {
+			dir = os.Args[2]
+		}
+		overlay := config.FindOverlay(dir)
+		if overlay == "" {
+			overlay = filepath.Join(config.ProjectRoot(dir), config.OverlayNames[0])
+		}
+		project, err := config.LoadWithOverlay(path, overlay)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading project config: %v\n", err)
+			os.Exit(1)
+		}
+		model = ui.NewProjectModel(project, overlay)
+		cfg = project
+	}
+
+	// Run the TUI
+	if errs := render.Validate(cfg); len(errs) == 1 {
+		model.Error = errs[0].Error()
+	} else if len(errs) > 1 {
+		model.Error = fmt.Sprintf("%d invalid values, first %s", len(errs), errs[0].Error())
+	}
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
@@ -27,3 +91,111 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runRender prints the statusline for the payload on stdin, with the
+// project overlay of the session's directory
+func runRender() {
+	p, err := payload.Read(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering statusline: %v\n", err)
+		os.Exit(1)
+	}
+	cfg, err := config.LoadFor(render.WorkDir(p))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	if err := render.Run(cfg, p, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering statusline: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runLint checks the config at path, or the default one, and prints each
+// invalid value. It exits with status 1 if there are any, for use in CI.
+func runLint(path string) {
+	if path == "" {
+		var err error
+		if path, err = config.GetConfigPath(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error finding config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading config: %v\n", err)
+		os.Exit(1)
+	}
+	cfg, err := config.Parse(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	errs := render.Validate(cfg)
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", path, e.Error())
+	}
+	if len(errs) > 0 {
+		os.Exit(1)
+	}
+}
+
+// runGitRefresh updates the cached git status of a repository that was too
+// slow to read within the render deadline
+func runGitRefresh(dir string) {
+	cache, err := git.DefaultCache()
+	if err != nil {
+		os.Exit(1)
+	}
+	if err := cache.Update(dir); err != nil {
+		os.Exit(1)
+	}
+}
+
+// runHook handles a Claude Code hook event read from stdin. Failures exit
+// with status 1, which Claude Code reports without blocking the session.
+func runHook(event string) {
+	in, err := hook.ReadInput(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading hook input: %v\n", err)
+		os.Exit(1)
+	}
+
+	dir := in.Cwd
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	cfg, err := config.LoadFor(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	store, err := state.DefaultStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening state: %v\n", err)
+		os.Exit(1)
+	}
+
+	env := notify.Env{
+		Runner:   notify.ExecRunner{},
+		Tmux:     os.Getenv("TMUX"),
+		TmuxPane: os.Getenv("TMUX_PANE"),
+	}
+	if tty := notify.OpenTTY(); tty != nil {
+		defer tty.Close()
+		env.TTY = tty
+	}
+
+	handler := &hook.Handler{Config: cfg, Store: store, Env: env, Now: time.Now}
+	err = handler.Handle(event, in)
+	// Clean up even when the handler failed
+	if gcErr := store.GC(time.Now()); gcErr != nil {
+		fmt.Fprintf(os.Stderr, "Error cleaning up state: %v\n", gcErr)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error handling %s hook: %v\n", event, err)
+		os.Exit(1)
+	}
+}
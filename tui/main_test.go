@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runAsMain makes the test binary run main when re-executed by runCommand
+const runAsMain = "STATUSLINE_TEST_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runAsMain) == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// quietConfig turns off the notifications a hook would send from the test
+const quietConfig = `{
+  "version": "2.0",
+  "notifications": {
+    "terminal_bell": {"enabled": false},
+    "desktop": {"enabled": false, "sound": false},
+    "tmux": {"enabled": false}
+  }
+}`
+
+// testHome returns a home directory holding config as the global config
+func testHome(t *testing.T, config string) string {
+	t.Helper()
+	home := t.TempDir()
+	path := filepath.Join(home, ".claude", ".statusline.config")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return home
+}
+
+// runCommand runs the binary with args and stdin in home, returning its
+// stdout, stderr and exit code
+func runCommand(t *testing.T, home, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = t.TempDir()
+	cmd.Env = append(os.Environ(), runAsMain+"=1", "HOME="+home, "TMUX=", "COLUMNS=")
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	err := cmd.Run()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return stdout.String(), stderr.String(), exit.ExitCode()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stdout.String(), stderr.String(), 0
+}
+
+func TestRender(t *testing.T) {
+	home := testHome(t, quietConfig)
+	payload := `{"session_id": "s1", "model": {"display_name": "Opus"}, "context_window": {"used_percentage": 12}}`
+
+	stdout, stderr, code := runCommand(t, home, payload, "render")
+	if code != 0 || stderr != "" {
+		t.Fatalf("render exited %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "Opus") {
+		t.Errorf("render printed %q, want the model", stdout)
+	}
+
+	_, stderr, code = runCommand(t, home, "{not json", "render")
+	if code != 1 || !strings.HasPrefix(stderr, "Error rendering statusline:") {
+		t.Errorf("bad payload: exit %d, stderr %q", code, stderr)
+	}
+}
+
+func TestLint(t *testing.T) {
+	home := testHome(t, quietConfig)
+	if _, stderr, code := runCommand(t, home, "", "lint"); code != 0 {
+		t.Errorf("lint of a valid config exited %d: %s", code, stderr)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"version": "2.0", "display": {"style": "fancy"}, "section_order": ["weather"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, stderr, code := runCommand(t, home, "", "lint", bad)
+	if code != 1 {
+		t.Errorf("lint of an invalid config exited %d, want 1", code)
+	}
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], bad+": display.style:") || !strings.HasPrefix(lines[1], bad+": section_order[0]:") {
+		t.Errorf("lint printed\n%s\nwant one line for display.style and one for section_order[0]", stderr)
+	}
+}
+
+func TestHookSetsWaiting(t *testing.T) {
+	home := testHome(t, quietConfig)
+	// The first hook of a fresh install runs before the state directory exists
+	if _, stderr, code := runCommand(t, home, `{"session_id": "s1", "hook_event_name": "SessionStart"}`, "hook"); code != 0 {
+		t.Fatalf("SessionStart hook exited %d: %s", code, stderr)
+	}
+
+	input := `{"session_id": "s1", "hook_event_name": "Notification", "message": "Claude is waiting for your input", "notification_type": "idle_prompt"}`
+	if _, stderr, code := runCommand(t, home, input, "hook"); code != 0 {
+		t.Fatalf("hook exited %d: %s", code, stderr)
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".claude", ".statusline-state.d", "s1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry struct {
+		Waiting bool   `json:"waiting"`
+		Type    string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatal(err)
+	}
+	if !entry.Waiting || entry.Type != "idle" {
+		t.Errorf("state after the hook = %s, want waiting on idle", data)
+	}
+
+	if _, _, code := runCommand(t, home, `{"session_id": "s1"}`, "hook", "Stop"); code != 1 {
+		t.Errorf("unsupported hook event exited %d, want 1", code)
+	}
+}
@@ -0,0 +1,70 @@
+package notify
+
+import "statusline-config/config"
+
+// Trigger pairs a channel with the usage percentage that sets it off
+type Trigger struct {
+	Channel   Channel
+	Threshold int
+}
+
+// thresholds are the threshold alert settings of one channel
+type thresholds struct {
+	OnContextPanic   bool
+	ContextThreshold int
+	OnSessionLimit   bool
+	SessionThreshold int
+}
+
+// thresholdChannel is a channel with its threshold alert settings
+type thresholdChannel struct {
+	channel    Channel
+	enabled    bool
+	thresholds thresholds
+}
+
+// thresholdChannels returns every channel that can raise threshold alerts
+func thresholdChannels(cfg *config.Notifications, env Env) []thresholdChannel {
+	bell, desktop, tmux := cfg.TerminalBell, cfg.Desktop, cfg.Tmux
+	channels := []thresholdChannel{
+		{&Bell{TTY: env.TTY}, bell.Enabled,
+			thresholds{bell.OnContextPanic, bell.ContextThreshold, bell.OnSessionLimit, bell.SessionThreshold}},
+		{&Desktop{Config: desktop, Runner: env.Runner}, desktop.Enabled || desktop.Sound,
+			thresholds{desktop.OnContextPanic, desktop.ContextThreshold, desktop.OnSessionLimit, desktop.SessionThreshold}},
+	}
+	if env.Tmux != "" {
+		// Only flash a message: the window style is reserved for waiting, which clears it again
+		tmux.SetWindowStyle = false
+		channels = append(channels, thresholdChannel{&Tmux{Config: tmux, Runner: env.Runner, Pane: env.TmuxPane}, tmux.Enabled,
+			thresholds{tmux.OnContextPanic, tmux.ContextThreshold, tmux.OnSessionLimit, tmux.SessionThreshold}})
+	}
+	return channels
+}
+
+// forThresholds returns the enabled channels whose alert is switched on by
+// on, each with the threshold picked out by threshold
+func forThresholds(cfg *config.Notifications, env Env, on func(thresholds) bool, threshold func(thresholds) int) []Trigger {
+	var triggers []Trigger
+	for _, c := range thresholdChannels(cfg, env) {
+		if c.enabled && on(c.thresholds) && threshold(c.thresholds) > 0 {
+			triggers = append(triggers, Trigger{Channel: c.channel, Threshold: threshold(c.thresholds)})
+		}
+	}
+	return triggers
+}
+
+// ForContext returns the channels configured to alert when context usage
+// crosses their own context_threshold
+func ForContext(cfg *config.Notifications, env Env) []Trigger {
+	return forThresholds(cfg, env,
+		func(t thresholds) bool { return t.OnContextPanic },
+		func(t thresholds) int { return t.ContextThreshold })
+}
+
+// ForSession returns the channels configured to alert when the session budget
+// reaches their own session_threshold percent
+func ForSession(cfg *config.Notifications, env Env) []Trigger {
+	return forThresholds(cfg, env,
+		func(t thresholds) bool { return t.OnSessionLimit },
+		func(t thresholds) int { return t.SessionThreshold })
+}
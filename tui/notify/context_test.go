@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"reflect"
+	"testing"
+
+	"statusline-config/config"
+)
+
+// triggerSummary names each trigger's channel with its threshold
+func triggerSummary(triggers []Trigger) map[string]int {
+	summary := map[string]int{}
+	for _, t := range triggers {
+		summary[t.Channel.Name()] = t.Threshold
+	}
+	return summary
+}
+
+func testNotifications() config.Notifications {
+	return config.Notifications{
+		TerminalBell: config.NotificationConfig{Enabled: true, OnContextPanic: true, ContextThreshold: 80, OnSessionLimit: true, SessionThreshold: 90},
+		Desktop:      config.DesktopNotification{Enabled: true, OnContextPanic: true, ContextThreshold: 70},
+		Tmux:         config.TmuxNotification{Enabled: true, OnContextPanic: true, ContextThreshold: 75, OnSessionLimit: true, SessionThreshold: 50, SetWindowStyle: true},
+	}
+}
+
+func TestForContext(t *testing.T) {
+	cfg := testNotifications()
+
+	got := triggerSummary(ForContext(&cfg, Env{}))
+	want := map[string]int{"terminal_bell": 80, "desktop": 70}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("outside tmux: %v, want %v", got, want)
+	}
+
+	got = triggerSummary(ForContext(&cfg, Env{Tmux: "/tmp/tmux-1000/default,1,0"}))
+	want["tmux"] = 75
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("inside tmux: %v, want %v", got, want)
+	}
+
+	cfg.Desktop.OnContextPanic = false
+	cfg.TerminalBell.ContextThreshold = 0
+	got = triggerSummary(ForContext(&cfg, Env{}))
+	if len(got) != 0 {
+		t.Errorf("with alerts off, disabled or without a threshold: %v, want none", got)
+	}
+}
+
+func TestForSession(t *testing.T) {
+	cfg := testNotifications()
+	got := triggerSummary(ForSession(&cfg, Env{Tmux: "/tmp/tmux-1000/default,1,0"}))
+	want := map[string]int{"terminal_bell": 90, "tmux": 50}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ForSession = %v, want %v", got, want)
+	}
+}
+
+func TestThresholdTmuxKeepsWindowStyle(t *testing.T) {
+	cfg := testNotifications()
+	for _, trigger := range ForContext(&cfg, Env{Tmux: "/tmp/tmux-1000/default,1,0"}) {
+		if tmux, ok := trigger.Channel.(*Tmux); ok && tmux.Config.SetWindowStyle {
+			t.Error("a context alert would restyle the tmux window, which only waiting clears")
+		}
+	}
+}
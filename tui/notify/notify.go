@@ -0,0 +1,190 @@
+// Package notify delivers alerts through the channels configured under
+// "notifications": the terminal bell, desktop popups and sounds, and tmux.
+// It also sets the terminal title.
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"statusline-config/config"
+)
+
+// Event is something the user should be alerted about
+type Event struct {
+	Title   string
+	Message string
+}
+
+// Channel delivers events to the user
+type Channel interface {
+	Name() string
+	Notify(ev Event) error
+}
+
+// Clearer is a channel whose alert persists until the wait is over
+type Clearer interface {
+	Clear() error
+}
+
+// Runner runs external commands; it is swapped out to test channels without side effects
+type Runner interface {
+	// Run runs a command to completion and returns its stdout
+	Run(name string, args ...string) ([]byte, error)
+	// Start launches a command without waiting for it to finish
+	Start(name string, args ...string) error
+	// LookPath reports whether a command is available
+	LookPath(name string) bool
+}
+
+// ExecRunner runs commands with os/exec
+type ExecRunner struct{}
+
+// Run runs a command to completion and returns its stdout
+func (ExecRunner) Run(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// Start launches a command without waiting for it to finish
+func (ExecRunner) Start(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// LookPath reports whether a command is available
+func (ExecRunner) LookPath(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// Env is what channels need from the outside world
+type Env struct {
+	Runner Runner
+	// TTY is the controlling terminal, or nil when there is none
+	TTY io.Writer
+	// Tmux and TmuxPane mirror $TMUX and $TMUX_PANE; Tmux is empty outside tmux
+	Tmux     string
+	TmuxPane string
+}
+
+// Dispatch sends the event to every channel, returning the first error
+func Dispatch(channels []Channel, ev Event) error {
+	var first error
+	for _, ch := range channels {
+		if err := ch.Notify(ev); err != nil && first == nil {
+			first = fmt.Errorf("%s: %w", ch.Name(), err)
+		}
+	}
+	return first
+}
+
+// Clear undoes lingering alerts on every channel that keeps one, returning the first error
+func Clear(channels []Channel) error {
+	var first error
+	for _, ch := range channels {
+		clearer, ok := ch.(Clearer)
+		if !ok {
+			continue
+		}
+		if err := clearer.Clear(); err != nil && first == nil {
+			first = fmt.Errorf("%s: %w", ch.Name(), err)
+		}
+	}
+	return first
+}
+
+// ForWaiting returns the channels enabled for "Claude needs your input" alerts
+func ForWaiting(cfg *config.Notifications, env Env) []Channel {
+	var channels []Channel
+	if cfg.TerminalBell.Enabled {
+		channels = append(channels, &Bell{TTY: env.TTY})
+	}
+	if cfg.Desktop.Enabled || cfg.Desktop.Sound {
+		channels = append(channels, &Desktop{Config: cfg.Desktop, Runner: env.Runner})
+	}
+	if cfg.Tmux.Enabled && env.Tmux != "" {
+		channels = append(channels, &Tmux{Config: cfg.Tmux, Runner: env.Runner, Pane: env.TmuxPane})
+	}
+	return channels
+}
+
+// Bell rings the terminal bell
+type Bell struct {
+	TTY io.Writer
+}
+
+// Name identifies the channel
+func (b *Bell) Name() string { return "terminal_bell" }
+
+// Notify rings the bell; without a terminal there is nothing to ring
+func (b *Bell) Notify(ev Event) error {
+	if b.TTY == nil {
+		return nil
+	}
+	_, err := io.WriteString(b.TTY, "\a")
+	return err
+}
+
+// Desktop shows a system notification and optionally plays a sound
+type Desktop struct {
+	Config config.DesktopNotification
+	Runner Runner
+}
+
+// Name identifies the channel
+func (d *Desktop) Name() string { return "desktop" }
+
+// Notify shows the popup (macOS or Linux) and plays the sound (macOS only)
+func (d *Desktop) Notify(ev Event) error {
+	var err error
+	if d.Config.Enabled {
+		err = d.popup(ev)
+	}
+	if d.Config.Sound {
+		if soundErr := d.sound(); err == nil {
+			err = soundErr
+		}
+	}
+	return err
+}
+
+func (d *Desktop) popup(ev Event) error {
+	switch {
+	case d.Runner.LookPath("osascript"):
+		script := fmt.Sprintf(`display notification %s with title %s sound name ""`,
+			appleScriptString(ev.Message), appleScriptString(ev.Title))
+		return d.Runner.Start("osascript", "-e", script)
+	case d.Runner.LookPath("notify-send"):
+		return d.Runner.Start("notify-send", "-u", "critical", ev.Title, ev.Message)
+	}
+	return nil
+}
+
+func (d *Desktop) sound() error {
+	if runtime.GOOS != "darwin" || !d.Runner.LookPath("afplay") {
+		return nil
+	}
+	path := d.Config.SoundPath
+	if path == "" {
+		path = "/System/Library/Sounds/Tink.aiff"
+	}
+	volume := d.Config.SoundVolume
+	if volume <= 0 {
+		volume = 1
+	}
+	return d.Runner.Start("afplay", "-v", fmt.Sprintf("%g", volume), path)
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
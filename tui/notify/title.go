@@ -0,0 +1,26 @@
+package notify
+
+import (
+	"io"
+	"strings"
+)
+
+// SetTitle sets the terminal's window and icon title with OSC 0. Without a
+// terminal it does nothing.
+func SetTitle(w io.Writer, title string) error {
+	if w == nil {
+		return nil
+	}
+	_, err := io.WriteString(w, "\033]0;"+stripControl(title)+"\a")
+	return err
+}
+
+// stripControl drops control characters that would end the sequence early
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"strings"
+
+	"statusline-config/config"
+)
+
+// defaultAlertStyle is applied to the window when no alert style is configured
+const defaultAlertStyle = "bg=red,fg=white,bold"
+
+// tmux window options used to remember an alert so it can be undone
+const (
+	tmuxWaitingOption = "@claude_waiting"
+	tmuxSavedOption   = "@claude_saved_style"
+	tmuxStyleOption   = "window-status-style"
+)
+
+// Tmux flashes a message in tmux and highlights the pane's window in the status bar
+type Tmux struct {
+	Config config.TmuxNotification
+	Runner Runner
+	// Pane is the target pane ($TMUX_PANE); empty targets the current pane
+	Pane string
+}
+
+// Name identifies the channel
+func (t *Tmux) Name() string { return "tmux" }
+
+// Notify shows the message and marks the window as waiting
+func (t *Tmux) Notify(ev Event) error {
+	if t.Config.DisplayMessage {
+		text := ev.Message
+		if ev.Title != "" {
+			text = ev.Title + ": " + text
+		}
+		// display-message expands #{...} formats, so escape literal hashes
+		text = strings.ReplaceAll(text, "#", "##")
+		if _, err := t.run("display-message", "", text); err != nil {
+			return err
+		}
+	}
+	if !t.Config.SetWindowStyle {
+		return nil
+	}
+
+	// Save the original style only once so repeated alerts don't overwrite it
+	waiting, err := t.run("show-options", "-wqv", tmuxWaitingOption)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(waiting) == "" {
+		original, err := t.run("show-options", "-wqv", tmuxStyleOption)
+		if err != nil {
+			return err
+		}
+		if original = strings.TrimSpace(original); original != "" {
+			if _, err := t.run("set-option", "-wq", tmuxSavedOption, original); err != nil {
+				return err
+			}
+		}
+	}
+
+	style := t.Config.AlertStyle
+	if style == "" {
+		style = defaultAlertStyle
+	}
+	if _, err := t.run("set-option", "-wq", tmuxStyleOption, style); err != nil {
+		return err
+	}
+	_, err = t.run("set-option", "-wq", tmuxWaitingOption, "1")
+	return err
+}
+
+// Clear restores the window style saved by Notify and drops the waiting mark
+func (t *Tmux) Clear() error {
+	if !t.Config.SetWindowStyle {
+		return nil
+	}
+	waiting, err := t.run("show-options", "-wqv", tmuxWaitingOption)
+	if err != nil || strings.TrimSpace(waiting) == "" {
+		return err
+	}
+
+	saved, err := t.run("show-options", "-wqv", tmuxSavedOption)
+	if err != nil {
+		return err
+	}
+	if saved = strings.TrimSpace(saved); saved == "" {
+		// The window inherited the global style, so unset the override
+		_, err = t.run("set-option", "-wqu", tmuxStyleOption)
+	} else {
+		_, err = t.run("set-option", "-wq", tmuxStyleOption, saved)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := t.run("set-option", "-wqu", tmuxSavedOption); err != nil {
+		return err
+	}
+	_, err = t.run("set-option", "-wqu", tmuxWaitingOption)
+	return err
+}
+
+// run invokes a tmux command with its flags, targeting the pane when one is known
+func (t *Tmux) run(command, flags string, args ...string) (string, error) {
+	argv := []string{command}
+	if flags != "" {
+		argv = append(argv, flags)
+	}
+	if t.Pane != "" {
+		argv = append(argv, "-t", t.Pane)
+	}
+	out, err := t.Runner.Run("tmux", append(argv, args...)...)
+	return string(out), err
+}
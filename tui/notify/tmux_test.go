@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"statusline-config/config"
+)
+
+// fakeTmux logs every call and keeps window options as files, enough for
+// show-options and set-option to behave like tmux
+const fakeTmux = `#!/bin/sh
+echo "$*" >> "$FAKE_TMUX_DIR/calls"
+cmd=$1; shift
+unset_opt=
+while [ $# -gt 0 ]; do
+	case "$1" in
+	-t) shift 2 ;;
+	-*u*) unset_opt=1; shift ;;
+	-*) shift ;;
+	*) break ;;
+	esac
+done
+case "$cmd" in
+show-options) cat "$FAKE_TMUX_DIR/opt$1" 2>/dev/null ;;
+set-option)
+	if [ -n "$unset_opt" ]; then
+		rm -f "$FAKE_TMUX_DIR/opt$1"
+	else
+		printf '%s\n' "$2" > "$FAKE_TMUX_DIR/opt$1"
+	fi ;;
+esac
+exit 0
+`
+
+// installFakeTmux puts the fake tmux first on PATH and returns its state directory
+func installFakeTmux(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake tmux is a shell script")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tmux"), []byte(fakeTmux), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("FAKE_TMUX_DIR", dir)
+	return dir
+}
+
+// tmuxCalls returns the tmux command lines run so far
+func tmuxCalls(t *testing.T, dir string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "calls"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// tmuxOption returns a window option set through the fake tmux, or "" if it is unset
+func tmuxOption(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "opt"+name))
+	if os.IsNotExist(err) {
+		return ""
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// setTmuxOption presets a window option, as the user's tmux.conf would
+func setTmuxOption(t *testing.T, dir, name, value string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "opt"+name), []byte(value+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestTmux() *Tmux {
+	return &Tmux{
+		Config: config.TmuxNotification{Enabled: true, DisplayMessage: true, SetWindowStyle: true, AlertStyle: "bg=yellow"},
+		Runner: ExecRunner{},
+		Pane:   "%3",
+	}
+}
+
+func TestTmuxNotifyAndClear(t *testing.T) {
+	dir := installFakeTmux(t)
+	setTmuxOption(t, dir, tmuxStyleOption, "fg=blue")
+	tmux := newTestTmux()
+
+	if err := tmux.Notify(Event{Title: "Permission Required", Message: "Run #{pane_id}?"}); err != nil {
+		t.Fatal(err)
+	}
+	if calls := tmuxCalls(t, dir); len(calls) == 0 || calls[0] != "display-message -t %3 Permission Required: Run ##{pane_id}?" {
+		t.Errorf("first call = %q, want the message with hashes escaped", calls)
+	}
+	if got := tmuxOption(t, dir, tmuxStyleOption); got != "bg=yellow" {
+		t.Errorf("window style = %q, want the alert style", got)
+	}
+	if got := tmuxOption(t, dir, tmuxWaitingOption); got != "1" {
+		t.Errorf("%s = %q, want 1", tmuxWaitingOption, got)
+	}
+
+	// A second alert while waiting must not save the alert style as the original
+	if err := tmux.Notify(Event{Message: "again"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := tmuxOption(t, dir, tmuxSavedOption); got != "fg=blue" {
+		t.Errorf("saved style = %q, want the original fg=blue", got)
+	}
+
+	if err := tmux.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if got := tmuxOption(t, dir, tmuxStyleOption); got != "fg=blue" {
+		t.Errorf("window style after Clear = %q, want fg=blue restored", got)
+	}
+	for _, name := range []string{tmuxWaitingOption, tmuxSavedOption} {
+		if got := tmuxOption(t, dir, name); got != "" {
+			t.Errorf("%s after Clear = %q, want it unset", name, got)
+		}
+	}
+}
+
+func TestTmuxClearUnsetsInheritedStyle(t *testing.T) {
+	dir := installFakeTmux(t)
+	tmux := newTestTmux()
+
+	if err := tmux.Notify(Event{Message: "waiting"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tmux.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if got := tmuxOption(t, dir, tmuxStyleOption); got != "" {
+		t.Errorf("window style after Clear = %q, want the override unset", got)
+	}
+}
+
+func TestTmuxClearWithoutAlert(t *testing.T) {
+	dir := installFakeTmux(t)
+	setTmuxOption(t, dir, tmuxStyleOption, "fg=blue")
+
+	if err := newTestTmux().Clear(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"show-options -wqv -t %3 " + tmuxWaitingOption}
+	if calls := tmuxCalls(t, dir); !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %q, want only the check for an alert", calls)
+	}
+}
+
+func TestTmuxMessageOnly(t *testing.T) {
+	dir := installFakeTmux(t)
+	tmux := newTestTmux()
+	tmux.Config.SetWindowStyle = false
+	tmux.Pane = ""
+
+	if err := tmux.Notify(Event{Message: "Context at 80%"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tmux.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"display-message Context at 80%"}
+	if calls := tmuxCalls(t, dir); !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+}
+
+func TestTmuxOutsideTmux(t *testing.T) {
+	dir := installFakeTmux(t)
+	// Only tmux, so that nothing real pops up
+	cfg := config.Notifications{Tmux: newTestTmux().Config}
+
+	channels := ForWaiting(&cfg, Env{Runner: ExecRunner{}})
+	for _, ch := range channels {
+		if ch.Name() == "tmux" {
+			t.Fatal("ForWaiting returned the tmux channel outside tmux")
+		}
+	}
+	Dispatch(channels, Event{Message: "waiting"})
+	Clear(channels)
+	if calls := tmuxCalls(t, dir); calls != nil {
+		t.Errorf("tmux was run outside tmux: %q", calls)
+	}
+
+	channels = ForWaiting(&cfg, Env{Runner: ExecRunner{}, Tmux: "/tmp/tmux-1000/default,1234,0"})
+	if len(channels) == 0 || channels[len(channels)-1].Name() != "tmux" {
+		t.Error("ForWaiting left out the tmux channel inside tmux")
+	}
+}
@@ -0,0 +1,22 @@
+package notify
+
+import (
+	"io"
+	"os"
+	"runtime"
+)
+
+// OpenTTY opens the controlling terminal for writing. Hooks and the
+// statusline have their stdout captured by Claude Code, so bells and escape
+// sequences must bypass it. It returns nil when there is no terminal.
+func OpenTTY() io.WriteCloser {
+	name := "/dev/tty"
+	if runtime.GOOS == "windows" {
+		name = "CONOUT$"
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY, 0)
+	if err != nil {
+		return nil
+	}
+	return f
+}
@@ -0,0 +1,148 @@
+// Package payload models the JSON document Claude Code writes to the
+// statusline command's stdin.
+package payload
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Payload is the full statusline input document
+type Payload struct {
+	HookEventName  string        `json:"hook_event_name"`
+	SessionID      string        `json:"session_id"`
+	TranscriptPath string        `json:"transcript_path"`
+	Cwd            string        `json:"cwd"`
+	Version        string        `json:"version"`
+	Model          Model         `json:"model"`
+	Workspace      Workspace     `json:"workspace"`
+	OutputStyle    OutputStyle   `json:"output_style"`
+	Cost           Cost          `json:"cost"`
+	ContextWindow  ContextWindow `json:"context_window"`
+
+	missing map[string]bool
+}
+
+// Model identifies the model in use
+type Model struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+}
+
+// Workspace holds the directories of the session
+type Workspace struct {
+	CurrentDir string `json:"current_dir"`
+	ProjectDir string `json:"project_dir"`
+}
+
+// OutputStyle is the active output style
+type OutputStyle struct {
+	Name string `json:"name"`
+}
+
+// Cost holds the running totals for the session
+type Cost struct {
+	TotalCostUSD       float64 `json:"total_cost_usd"`
+	TotalDurationMs    int64   `json:"total_duration_ms"`
+	TotalAPIDurationMs int64   `json:"total_api_duration_ms"`
+	TotalLinesAdded    int     `json:"total_lines_added"`
+	TotalLinesRemoved  int     `json:"total_lines_removed"`
+}
+
+// ContextWindow describes how full the context window is
+type ContextWindow struct {
+	TotalInputTokens    int     `json:"total_input_tokens"`
+	TotalOutputTokens   int     `json:"total_output_tokens"`
+	ContextWindowSize   int     `json:"context_window_size"`
+	UsedPercentage      float64 `json:"used_percentage"`
+	RemainingPercentage float64 `json:"remaining_percentage"`
+}
+
+// Read parses a payload from r
+func Read(r io.Reader) (*Payload, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes a payload, ignoring unknown fields and recording which known
+// fields were absent, null or of the wrong type. An empty document yields a
+// payload with every field missing.
+func Parse(data []byte) (*Payload, error) {
+	p := &Payload{}
+
+	raw := map[string]interface{}{}
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		// Wrongly typed fields are left at their zero value and reported as missing
+		var typeErr *json.UnmarshalTypeError
+		if err := json.Unmarshal(data, p); err != nil && !errors.As(err, &typeErr) {
+			return nil, err
+		}
+	}
+
+	p.missing = map[string]bool{}
+	collectMissing(reflect.TypeOf(*p), raw, "", p.missing)
+	return p, nil
+}
+
+// collectMissing records every leaf field of t that has no usable value in raw
+func collectMissing(t reflect.Type, raw map[string]interface{}, prefix string, missing map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		path := prefix + name
+		value, ok := raw[name]
+
+		if field.Type.Kind() == reflect.Struct {
+			obj, _ := value.(map[string]interface{})
+			collectMissing(field.Type, obj, path+".", missing)
+			continue
+		}
+		if !ok || value == nil || !sameKind(field.Type.Kind(), value) {
+			missing[path] = true
+		}
+	}
+}
+
+// sameKind reports whether a decoded JSON value fits a Go field of kind k
+func sameKind(k reflect.Kind, value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return k == reflect.String
+	case float64:
+		if k == reflect.Float32 || k == reflect.Float64 {
+			return true
+		}
+		return k >= reflect.Int && k <= reflect.Int64 && v == float64(int64(v))
+	case bool:
+		return k == reflect.Bool
+	}
+	return false
+}
+
+// Has reports whether the field at the dotted JSON path was provided
+func (p *Payload) Has(path string) bool {
+	return !p.missing[path]
+}
+
+// Missing returns the dotted JSON paths of all fields that were not provided
+func (p *Payload) Missing() []string {
+	paths := make([]string, 0, len(p.missing))
+	for path := range p.missing {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,118 @@
+package payload
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const fullPayload = `{
+  "hook_event_name": "Status",
+  "session_id": "abc123",
+  "transcript_path": "/home/u/.claude/projects/p/abc123.jsonl",
+  "cwd": "/home/u/src/app/web",
+  "version": "1.0.80",
+  "model": {"id": "claude-opus-4-1", "display_name": "Opus"},
+  "workspace": {"current_dir": "/home/u/src/app/web", "project_dir": "/home/u/src/app"},
+  "output_style": {"name": "default"},
+  "cost": {
+    "total_cost_usd": 0.42,
+    "total_duration_ms": 65000,
+    "total_api_duration_ms": 21000,
+    "total_lines_added": 120,
+    "total_lines_removed": 8
+  },
+  "context_window": {
+    "total_input_tokens": 52000,
+    "total_output_tokens": 3100,
+    "context_window_size": 200000,
+    "used_percentage": 26.5,
+    "remaining_percentage": 73.5
+  },
+  "some_future_field": {"nested": true}
+}`
+
+func TestParseFull(t *testing.T) {
+	p, err := Parse([]byte(fullPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Model.DisplayName != "Opus" || p.Workspace.ProjectDir != "/home/u/src/app" {
+		t.Errorf("model %q, project dir %q", p.Model.DisplayName, p.Workspace.ProjectDir)
+	}
+	if p.Cost.TotalCostUSD != 0.42 || p.Cost.TotalLinesAdded != 120 {
+		t.Errorf("cost %v, lines added %d", p.Cost.TotalCostUSD, p.Cost.TotalLinesAdded)
+	}
+	if p.ContextWindow.UsedPercentage != 26.5 || p.ContextWindow.ContextWindowSize != 200000 {
+		t.Errorf("context window %+v", p.ContextWindow)
+	}
+	if missing := p.Missing(); len(missing) != 0 {
+		t.Errorf("Missing() = %q, want none", missing)
+	}
+}
+
+func TestParseMissingFields(t *testing.T) {
+	p, err := Parse([]byte(`{
+	  "session_id": null,
+	  "model": "opus",
+	  "cost": {"total_cost_usd": "0.42", "total_duration_ms": 1.5},
+	  "context_window": {"used_percentage": 40}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]bool{
+		"session_id":                     false, // null
+		"cwd":                            false, // absent
+		"model.display_name":             false, // model is not an object
+		"cost.total_cost_usd":            false, // string for a number
+		"cost.total_duration_ms":         false, // fraction for an integer
+		"context_window.used_percentage": true,
+	}
+	for path, want := range tests {
+		if got := p.Has(path); got != want {
+			t.Errorf("Has(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if p.ContextWindow.UsedPercentage != 40 {
+		t.Errorf("fields after a wrongly typed one were not read: %+v", p.ContextWindow)
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	for _, input := range []string{"", "  \n"} {
+		p, err := Parse([]byte(input))
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		want := leafCount(reflect.TypeOf(*p))
+		if got := len(p.Missing()); got != want {
+			t.Errorf("Parse(%q) is missing %d fields, want all %d", input, got, want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, input := range []string{"{", "[1, 2]", `"text"`} {
+		if _, err := Read(strings.NewReader(input)); err == nil {
+			t.Errorf("Read(%q) succeeded, want an error", input)
+		}
+	}
+}
+
+// leafCount counts the JSON leaf fields of a struct type
+func leafCount(t reflect.Type) int {
+	n := 0
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		switch {
+		case !f.IsExported():
+		case f.Type.Kind() == reflect.Struct:
+			n += leafCount(f.Type)
+		default:
+			n++
+		}
+	}
+	return n
+}
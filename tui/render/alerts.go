@@ -0,0 +1,93 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+
+	"statusline-config/notify"
+	"statusline-config/state"
+)
+
+// errNoChange aborts the state update when no alert fired or re-armed
+var errNoChange = errors.New("no alert changed")
+
+// notifyContext alerts when context usage crosses each channel's context threshold
+func notifyContext(ctx *Context, env notify.Env) error {
+	triggers := notify.ForContext(&ctx.Config.Notifications, env)
+	return notifyCrossings(ctx, "context", ctx.Percent(), triggers, contextEvent(ctx))
+}
+
+// notifyBudget alerts when session spend crosses each channel's session threshold
+func notifyBudget(ctx *Context, env notify.Env) error {
+	budget := ctx.Config.Budget
+	if budget.SessionUSD <= 0 {
+		return nil
+	}
+	triggers := notify.ForSession(&ctx.Config.Notifications, env)
+	pct := budget.Percent(ctx.Payload.Cost.TotalCostUSD)
+	return notifyCrossings(ctx, "budget", pct, triggers, budgetEvent(ctx, pct))
+}
+
+// notifyCrossings fires the triggers whose threshold pct has reached since the
+// last render and re-arms those it has dropped below, e.g. after /compact.
+// Which alerts fired is remembered per session under the given kind.
+func notifyCrossings(ctx *Context, kind string, pct int, triggers []notify.Trigger, ev notify.Event) error {
+	if ctx.Store == nil || len(triggers) == 0 {
+		return nil
+	}
+
+	var fire []notify.Channel
+	err := ctx.Store.Update(ctx.Payload.SessionID, func(e *state.Entry) error {
+		changed := false
+		for _, t := range triggers {
+			key := kind + ":" + t.Channel.Name()
+			over := pct >= t.Threshold
+			if over == e.Alerts[key] {
+				continue
+			}
+			changed = true
+			if !over {
+				delete(e.Alerts, key)
+				continue
+			}
+			if e.Alerts == nil {
+				e.Alerts = map[string]bool{}
+			}
+			e.Alerts[key] = true
+			fire = append(fire, t.Channel)
+		}
+		if !changed {
+			return errNoChange
+		}
+		return nil
+	})
+	if err == errNoChange || len(fire) == 0 {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return notify.Dispatch(fire, ev)
+}
+
+// contextEvent describes a context alert, titled by the desktop notification title
+func contextEvent(ctx *Context) notify.Event {
+	pct := ctx.Percent()
+	title := ctx.Config.Notifications.Desktop.Title
+	if title == "" {
+		title = "Context is filling up"
+	}
+	message := fmt.Sprintf("Context at %d%%", pct)
+	if model := ctx.Payload.Model.DisplayName; model != "" {
+		message = fmt.Sprintf("%s is at %d%% of its context window", model, pct)
+	}
+	return notify.Event{Title: title, Message: message}
+}
+
+// budgetEvent describes a session budget alert
+func budgetEvent(ctx *Context, pct int) notify.Event {
+	return notify.Event{
+		Title:   fmt.Sprintf("Session budget %d%% spent", pct),
+		Message: fmt.Sprintf("$%.2f of $%.2f spent this session", ctx.Payload.Cost.TotalCostUSD, ctx.Config.Budget.SessionUSD),
+	}
+}
@@ -0,0 +1,62 @@
+package render
+
+import (
+	"testing"
+
+	"statusline-config/notify"
+	"statusline-config/state"
+)
+
+// countingChannel counts the alerts sent to it
+type countingChannel struct {
+	name  string
+	count int
+}
+
+func (c *countingChannel) Name() string { return c.name }
+
+func (c *countingChannel) Notify(ev notify.Event) error {
+	c.count++
+	return nil
+}
+
+func TestNotifyCrossings(t *testing.T) {
+	ctx := testContext(t, `{"session_id": "s1"}`)
+	ctx.Store = &state.Store{Dir: t.TempDir()}
+	low := &countingChannel{name: "low"}
+	high := &countingChannel{name: "high"}
+	triggers := []notify.Trigger{{Channel: low, Threshold: 50}, {Channel: high, Threshold: 80}}
+
+	steps := []struct {
+		pct       int
+		low, high int // Alerts sent so far
+		note      string
+	}{
+		{40, 0, 0, "below both"},
+		{55, 1, 0, "crosses the low threshold"},
+		{70, 1, 0, "still over, no repeat"},
+		{85, 1, 1, "crosses the high threshold"},
+		{90, 1, 1, "no repeats"},
+		{30, 1, 1, "/compact re-arms both"},
+		{60, 2, 1, "crosses the low threshold again"},
+	}
+	for _, step := range steps {
+		if err := notifyCrossings(ctx, "context", step.pct, triggers, notify.Event{}); err != nil {
+			t.Fatal(err)
+		}
+		if low.count != step.low || high.count != step.high {
+			t.Errorf("at %d%% (%s): sent %d low and %d high, want %d and %d",
+				step.pct, step.note, low.count, high.count, step.low, step.high)
+		}
+	}
+
+	// Another session has its own memory
+	other := testContext(t, `{"session_id": "s2"}`)
+	other.Store = ctx.Store
+	if err := notifyCrossings(other, "context", 60, triggers, notify.Event{}); err != nil {
+		t.Fatal(err)
+	}
+	if low.count != 3 {
+		t.Errorf("a second session over the threshold sent %d low alerts in all, want 3", low.count)
+	}
+}
@@ -0,0 +1,145 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+
+	"statusline-config/config"
+)
+
+// renderDirectory shows the working directory in the configured style, shortened if too wide
+func renderDirectory(ctx *Context) string {
+	dir := ctx.WorkDir
+	if dir == "" {
+		return ""
+	}
+	project := ctx.Payload.Workspace.ProjectDir
+	if !ctx.Payload.Has("workspace.project_dir") {
+		project = ""
+	}
+
+	thresholds := ctx.Config.Thresholds
+	style := ctx.Config.Display.DirectoryStyle
+	text := Directory(style, dir, project, homeDir())
+	if runewidth.StringWidth(text) > thresholds.DirectoryMaxLength {
+		switch style {
+		case config.DirectoryRelative, config.DirectoryFish:
+			// The deepest directory is the one worth keeping
+			text = truncateLeft(text, thresholds.DirectoryTruncateTo)
+		default:
+			// Keep both ends, so "payments-service-v2" still ends in "-v2"
+			text = truncateMiddle(text, thresholds.DirectoryMaxLength)
+		}
+	}
+	return ctx.Paint(ctx.Config.Colors.Directory, ctx.Config.Icons.Directory+" "+text)
+}
+
+// shortDirectory shows just the directory name without its icon, cut to
+// DirectoryTruncateTo around the middle
+func shortDirectory(ctx *Context) string {
+	if ctx.WorkDir == "" {
+		return ""
+	}
+	name := filepath.Base(ctx.WorkDir)
+	if truncate := ctx.Config.Thresholds.DirectoryTruncateTo; runewidth.StringWidth(name) > truncate {
+		name = truncateMiddle(name, truncate)
+	}
+	return ctx.Paint(ctx.Config.Colors.Directory, name)
+}
+
+// Directory formats dir in the given style. project is the session's project
+// root and home the user's home directory; either may be empty.
+func Directory(style, dir, project, home string) string {
+	dir = filepath.Clean(dir)
+	switch style {
+	case config.DirectoryRelative:
+		if project != "" {
+			project = filepath.Clean(project)
+			if rel, err := filepath.Rel(project, dir); err == nil && !strings.HasPrefix(rel, "..") {
+				return filepath.ToSlash(filepath.Join(filepath.Base(project), rel))
+			}
+		}
+		// Outside the project the path from home is the next best thing
+		return tildify(dir, home)
+	case config.DirectoryFish:
+		parts := strings.Split(tildify(dir, home), "/")
+		for i := 0; i < len(parts)-1; i++ {
+			parts[i] = abbreviate(parts[i])
+		}
+		return strings.Join(parts, "/")
+	case config.DirectoryEllipsis:
+		return tildify(dir, home)
+	}
+	return filepath.Base(dir)
+}
+
+// tildify replaces the home directory prefix of dir with ~
+func tildify(dir, home string) string {
+	dir = filepath.ToSlash(dir)
+	if home == "" {
+		return dir
+	}
+	home = filepath.ToSlash(filepath.Clean(home))
+	if dir == home {
+		return "~"
+	}
+	if strings.HasPrefix(dir, home+"/") {
+		return "~" + dir[len(home):]
+	}
+	return dir
+}
+
+// abbreviate cuts a path element to its first character, keeping a leading dot
+func abbreviate(name string) string {
+	runes := []rune(name)
+	if len(runes) > 1 && runes[0] == '.' {
+		return string(runes[:2])
+	}
+	if len(runes) > 0 {
+		return string(runes[:1])
+	}
+	return name
+}
+
+// truncateLeft keeps the last width columns of s behind a "..." prefix
+func truncateLeft(s string, width int) string {
+	runes := []rune(s)
+	start := len(runes)
+	for w := 0; start > 0; start-- {
+		w += runewidth.RuneWidth(runes[start-1])
+		if w > width {
+			break
+		}
+	}
+	return "..." + string(runes[start:])
+}
+
+// truncateMiddle fits s into width columns by replacing its middle with "…"
+func truncateMiddle(s string, width int) string {
+	if width < 2 {
+		return runewidth.Truncate(s, width, "")
+	}
+	runes := []rune(s)
+	// The tail gets the larger half: it names the directory we are in
+	headWidth := (width - 1) / 2
+	tailWidth := width - 1 - headWidth
+
+	head := runewidth.Truncate(s, headWidth, "")
+	end := len(runes)
+	for w := 0; end > 0; end-- {
+		w += runewidth.RuneWidth(runes[end-1])
+		if w > tailWidth {
+			break
+		}
+	}
+	return head + "…" + string(runes[end:])
+}
+
+// homeDir returns the user's home directory, or "" if it is unknown
+func homeDir() string {
+	home, _ := os.UserHomeDir()
+	return home
+}
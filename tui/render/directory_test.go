@@ -0,0 +1,74 @@
+package render
+
+import (
+	"testing"
+
+	"statusline-config/config"
+)
+
+func TestDirectory(t *testing.T) {
+	const home, project = "/home/u", "/home/u/src/app"
+	tests := []struct {
+		style, dir, project, home string
+		want                      string
+	}{
+		{config.DirectoryBasename, "/home/u/src/app/web", project, home, "web"},
+		{config.DirectoryRelative, "/home/u/src/app/web/", project, home, "app/web"},
+		{config.DirectoryRelative, project, project, home, "app"},
+		{config.DirectoryRelative, "/home/u/notes", project, home, "~/notes"},
+		{config.DirectoryRelative, "/home/u/src/application", project, home, "~/src/application"},
+		{config.DirectoryRelative, "/home/u/src/app/web", "", home, "~/src/app/web"},
+		{config.DirectoryFish, "/home/u/src/app/web", project, home, "~/s/a/web"},
+		{config.DirectoryFish, "/home/u/.config/nvim", project, home, "~/.c/nvim"},
+		{config.DirectoryEllipsis, "/home/u/src/app/web", project, home, "~/src/app/web"},
+		{config.DirectoryEllipsis, "/srv/app", project, home, "/srv/app"},
+		{config.DirectoryEllipsis, "/home/u", project, home, "~"},
+		{config.DirectoryEllipsis, "/home/user2/app", project, home, "/home/user2/app"},
+	}
+	for _, tt := range tests {
+		if got := Directory(tt.style, tt.dir, tt.project, tt.home); got != tt.want {
+			t.Errorf("Directory(%s, %s, %q) = %q, want %q", tt.style, tt.dir, tt.project, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateDirectory(t *testing.T) {
+	if got, want := truncateMiddle("~/src/statusline/render", 12), "~/src…render"; got != want {
+		t.Errorf("truncateMiddle = %q, want %q", got, want)
+	}
+	if got, want := truncateMiddle("日本語のディレクトリ", 7), "日…リ"; got != want {
+		t.Errorf("truncateMiddle of wide runes = %q, want %q", got, want)
+	}
+	if got, want := truncateLeft("app/web/components", 10), "...components"; got != want {
+		t.Errorf("truncateLeft = %q, want %q", got, want)
+	}
+}
+
+func TestRenderDirectoryShortens(t *testing.T) {
+	ctx := testContext(t, `{"workspace": {"current_dir": "/srv/app/web/components/buttons", "project_dir": "/srv/app"}}`)
+	ctx.WorkDir = "/srv/app/web/components/buttons"
+	ctx.Config.Thresholds.DirectoryMaxLength = 20
+	ctx.Config.Thresholds.DirectoryTruncateTo = 12
+	icon := ctx.Config.Icons.Directory + " "
+
+	tests := map[string]string{
+		config.DirectoryRelative: icon + "...ents/buttons",
+		config.DirectoryFish:     icon + "/s/a/w/c/buttons",
+		config.DirectoryEllipsis: icon + "/srv/app/…ts/buttons",
+		config.DirectoryBasename: icon + "buttons",
+	}
+	for style, want := range tests {
+		ctx.Config.Display.DirectoryStyle = style
+		if got := renderDirectory(ctx); got != want {
+			t.Errorf("%s: %q, want %q", style, got, want)
+		}
+	}
+
+	// A long basename keeps its suffix
+	ctx.WorkDir = "/srv/payments-service-v2"
+	ctx.Config.Thresholds.DirectoryMaxLength = 15
+	ctx.Config.Display.DirectoryStyle = config.DirectoryBasename
+	if got, want := renderDirectory(ctx), icon+"payment…vice-v2"; got != want {
+		t.Errorf("long basename: %q, want %q", got, want)
+	}
+}
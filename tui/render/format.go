@@ -0,0 +1,251 @@
+package render
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// formatName is the template name that prefixes text/template errors
+const formatName = "format"
+
+// formatAliases are shorter names for segments in Display.Format
+var formatAliases = map[string]string{
+	"Dir":     "directory",
+	"Moons":   "context_moons",
+	"Gauge":   "context_moons",
+	"Tokens":  "token_count",
+	"Waiting": "waiting_indicator",
+}
+
+// FormatError is a Display.Format error at a position in the template
+type FormatError struct {
+	Line int
+	Col  int // 0 when text/template does not say
+	Msg  string
+}
+
+func (e *FormatError) Error() string {
+	if e.Col > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Col, e.Msg)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// ParseFormat compiles a Display.Format template. Helper functions are bound
+// to ctx when the template is executed by RenderFormat.
+func ParseFormat(format string) (*template.Template, error) {
+	tmpl, err := template.New(formatName).
+		Option("missingkey=error").
+		Funcs(formatFuncs(nil)).
+		Parse(format)
+	if err != nil {
+		return nil, formatError(format, err)
+	}
+	return tmpl, nil
+}
+
+// ValidateFormat parses format and runs it over the preview session, so that
+// misspelled segment names are caught along with syntax errors
+func ValidateFormat(ctx *Context, format string) error {
+	if strings.TrimSpace(format) == "" {
+		return nil
+	}
+	tmpl, err := ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	_, err = RenderFormat(ctx, tmpl)
+	return err
+}
+
+// RenderFormat executes a parsed Display.Format template for ctx
+func RenderFormat(ctx *Context, tmpl *template.Template) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Funcs(formatFuncs(ctx)).Execute(&b, FormatData(ctx, formatFields(tmpl))); err != nil {
+		return "", formatError("", err)
+	}
+	return b.String(), nil
+}
+
+// FormatData returns the values a Display.Format template can use: every
+// registered segment under its CamelCase name (e.g. .GitAheadBehind), the
+// aliases in formatAliases, and the raw session values. Only the names in
+// used are filled in, so segments the template does not show are never
+// rendered; a nil used fills in every name.
+func FormatData(ctx *Context, used map[string]bool) map[string]interface{} {
+	values := map[string]func() interface{}{
+		"Payload":    func() interface{} { return ctx.Payload },
+		"ContextPct": func() interface{} { return ctx.Percent() },
+		"CostUSD":    func() interface{} { return ctx.Payload.Cost.TotalCostUSD },
+		"Branch":     func() interface{} { return gitBranch(ctx) },
+		"Sep":        func() interface{} { return ctx.Config.Display.Separator },
+	}
+	for _, seg := range Registered() {
+		seg := seg
+		values[camelCase(seg.Name())] = func() interface{} { return seg.Render(ctx) }
+	}
+	for alias, name := range formatAliases {
+		if value, ok := values[camelCase(name)]; ok {
+			values[alias] = value
+		}
+	}
+
+	data := map[string]interface{}{}
+	for name, value := range values {
+		if used == nil || used[name] {
+			data[name] = value()
+		}
+	}
+	return data
+}
+
+// formatFields returns the names tmpl reads from its data, such as Model for
+// {{.Model | color "red"}}, or nil when it uses the data as a whole, as
+// {{.}} does
+func formatFields(tmpl *template.Template) map[string]bool {
+	used := map[string]bool{}
+	whole := false
+	var walk func(node parse.Node)
+	branch := func(b *parse.BranchNode) {
+		walk(b.Pipe)
+		walk(b.List)
+		if b.ElseList != nil {
+			walk(b.ElseList)
+		}
+	}
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.ChainNode:
+			walk(n.Node)
+		case *parse.FieldNode:
+			used[n.Ident[0]] = true
+		case *parse.VariableNode:
+			// $ is the data itself, $.Model one of its names
+			if n.Ident[0] == "$" {
+				if len(n.Ident) > 1 {
+					used[n.Ident[1]] = true
+				} else {
+					whole = true
+				}
+			}
+		case *parse.DotNode:
+			whole = true
+		case *parse.IfNode:
+			branch(&n.BranchNode)
+		case *parse.RangeNode:
+			branch(&n.BranchNode)
+		case *parse.WithNode:
+			branch(&n.BranchNode)
+		case *parse.TemplateNode:
+			if n.Pipe != nil {
+				walk(n.Pipe)
+			}
+		}
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			walk(t.Tree.Root)
+		}
+	}
+	if whole {
+		return nil
+	}
+	return used
+}
+
+// formatFuncs returns the helper functions available in Display.Format. With
+// a nil ctx, color leaves text unpainted; that is enough for parsing.
+func formatFuncs(ctx *Context) template.FuncMap {
+	return template.FuncMap{
+		// color paints text with a config color value: {{.Model | color "bright_red"}}
+		"color": func(value, text string) string {
+			if ctx == nil {
+				return text
+			}
+			return ctx.Paint(value, text)
+		},
+		// truncate cuts text to n cells, ending in "…": {{.Dir | truncate 12}}
+		"truncate": func(n int, text string) string {
+			return truncateWidth(text, n)
+		},
+		// pad fills text with spaces on the right up to n cells
+		"pad": func(n int, text string) string {
+			return text + strings.Repeat(" ", clamp(n-Width(text), 0, n))
+		},
+		// padLeft fills text with spaces on the left up to n cells
+		"padLeft": func(n int, text string) string {
+			return strings.Repeat(" ", clamp(n-Width(text), 0, n)) + text
+		},
+		// width returns the number of cells text occupies
+		"width": Width,
+	}
+}
+
+// camelCase turns a segment name like api_time into APITime
+func camelCase(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		switch word {
+		case "":
+		case "api":
+			b.WriteString("API")
+		default:
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+// templateError matches "template: format:LINE[:COL]: message"
+var templateError = regexp.MustCompile(`^template: ` + formatName + `:(\d+):(?:(\d+):)? (.*)$`)
+
+// quoted matches the first quoted token in an error message
+var quoted = regexp.MustCompile(`"([^"]+)"|<([^>]+)>`)
+
+// formatError converts a text/template error into a FormatError. Parse errors
+// only carry a line, so the column is taken from where the offending token
+// first appears on it.
+func formatError(format string, err error) error {
+	m := templateError.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	line, _ := strconv.Atoi(m[1])
+	col, _ := strconv.Atoi(m[2])
+	if m[2] != "" {
+		// text/template counts columns from 0
+		col++
+	}
+	msg := m[3]
+	// Exec errors say where: `executing "format" at <.Gti>: map has no entry ...`
+	msg = strings.TrimPrefix(msg, `executing "`+formatName+`" at `)
+
+	if col == 0 {
+		lines := strings.Split(format, "\n")
+		if tok := quoted.FindStringSubmatch(msg); tok != nil && line >= 1 && line <= len(lines) {
+			token := tok[1] + tok[2]
+			if i := strings.Index(lines[line-1], token); i >= 0 {
+				col = len([]rune(lines[line-1][:i])) + 1
+			}
+		}
+	}
+	return &FormatError{Line: line, Col: col, Msg: msg}
+}
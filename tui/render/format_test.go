@@ -0,0 +1,117 @@
+package render
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// formatContext returns a context for a session on Opus at 45% context
+func formatContext(t *testing.T) *Context {
+	t.Helper()
+	return testContext(t, `{"model": {"display_name": "Opus"}, "context_window": {"used_percentage": 45}, "cost": {"total_cost_usd": 1.5}}`)
+}
+
+func TestRenderFormat(t *testing.T) {
+	ctx := formatContext(t)
+	ctx.Config.Display.Format = "[{{.Model}}]{{.Sep}}{{.ContextPct}}% ${{.CostUSD}} {{.Waiting}}end"
+
+	model, _ := Lookup("model")
+	want := "[" + model.Render(ctx) + "]" + ctx.Config.Display.Separator + "45% $1.5 end"
+	if got := Render(ctx); got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
+
+func TestFormatHelpers(t *testing.T) {
+	ctx := formatContext(t)
+	tmpl, err := ParseFormat(`{{"statusline" | truncate 6}}|{{"ab" | pad 4}}|{{"ab" | padLeft 4}}|{{width "🌕x"}}|{{"abcdef" | pad 2}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := RenderFormat(ctx, tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "statu…|ab  |  ab|3|abcdef"; got != want {
+		t.Errorf("RenderFormat = %q, want %q", got, want)
+	}
+}
+
+func TestFormatNames(t *testing.T) {
+	data := FormatData(formatContext(t), nil)
+	for _, r := range Registered() {
+		if _, ok := data[camelCase(r.Name())]; !ok {
+			t.Errorf("segment %s has no format name", r.Name())
+		}
+	}
+	tests := map[string]string{"api_time": "APITime", "git_ahead_behind": "GitAheadBehind", "model": "Model"}
+	for name, want := range tests {
+		if got := camelCase(name); got != want {
+			t.Errorf("camelCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestFormatFields(t *testing.T) {
+	tests := []struct {
+		format string
+		want   map[string]bool
+	}{
+		{`{{.Model | color "red"}} {{if .Waiting}}{{$.Dir}}{{else}}{{.Payload.Model.ID}}{{end}}`,
+			map[string]bool{"Model": true, "Waiting": true, "Dir": true, "Payload": true}},
+		{`{{with .Git}}{{.}}{{end}}`, nil},
+		{`plain text`, map[string]bool{}},
+	}
+	for _, tt := range tests {
+		tmpl, err := ParseFormat(tt.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := formatFields(tmpl); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("formatFields(%q) = %v, want %v", tt.format, got, tt.want)
+		}
+	}
+
+	// Only what the template reads is rendered
+	data := FormatData(formatContext(t), map[string]bool{"Model": true, "Dir": true})
+	if len(data) != 2 || data["Model"] == nil || data["Dir"] == nil {
+		t.Errorf("FormatData filled in %v, want Model and Dir only", data)
+	}
+}
+
+func TestValidateFormatErrors(t *testing.T) {
+	tests := []struct {
+		format    string
+		line, col int
+	}{
+		{"{{.Model}} {{.Gti}}", 1, 14},
+		{"{{.Model}}\n  {{.Model | colour \"red\"}}", 2, 14},
+		{"{{.Model}}\n{{.Model", 2, 0},
+	}
+	for _, tt := range tests {
+		err := ValidateFormat(formatContext(t), tt.format)
+		var fe *FormatError
+		if !errors.As(err, &fe) {
+			t.Errorf("ValidateFormat(%q) = %v, want a FormatError", tt.format, err)
+			continue
+		}
+		if fe.Line != tt.line || fe.Col != tt.col {
+			t.Errorf("ValidateFormat(%q) at line %d, column %d (%v), want line %d, column %d",
+				tt.format, fe.Line, fe.Col, fe, tt.line, tt.col)
+		}
+	}
+
+	if err := ValidateFormat(formatContext(t), "  "); err != nil {
+		t.Errorf("a blank format is an error: %v", err)
+	}
+}
+
+func TestRenderFallsBackFromBadFormat(t *testing.T) {
+	ctx := formatContext(t)
+	want := Render(ctx)
+	ctx.Config.Display.Format = "{{.Gti}}"
+	if got := Render(ctx); got != want {
+		t.Errorf("with a bad format Render = %q, want the sections %q", got, want)
+	}
+}
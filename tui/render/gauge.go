@@ -0,0 +1,61 @@
+package render
+
+import (
+	"strings"
+
+	"statusline-config/config"
+)
+
+// Partial cells for the block and braille gauges, from emptiest to fullest
+var (
+	blockEighths   = []string{"▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+	brailleEighths = []string{"⠀", "⡀", "⡄", "⡆", "⡇", "⣇", "⣧", "⣷", "⣿"}
+)
+
+// renderGauge draws the context usage in the configured gauge style
+func renderGauge(ctx *Context) string {
+	gauge := ctx.Config.Display.Gauge
+	return Gauge(ctx.Config, gauge.Style, ctx.Percent())
+}
+
+// Gauge draws pct in the given style using the config's widths and moon icons
+func Gauge(cfg *config.Config, style string, pct int) string {
+	pct = clamp(pct, 0, 100)
+	width := cfg.Display.Gauge.Width(style)
+
+	switch style {
+	case config.GaugeBlocks:
+		return cellGauge(width, pct, "█", "░", blockEighths)
+	case config.GaugeBraille:
+		return cellGauge(width, pct, "⣿", "⠀", brailleEighths[1:len(brailleEighths)-1])
+	case config.GaugeASCII:
+		filled := (pct*width + 50) / 100
+		return "[" + strings.Repeat("#", filled) + strings.Repeat(".", width-filled) + "]"
+	}
+
+	// Moons: each cell covers an equal slice of the context window, measured
+	// as statusline.sh does for three moons: (pct - 33*i) * 3
+	step := 100 / width
+	var b strings.Builder
+	for i := 0; i < width; i++ {
+		b.WriteString(moonFor(cfg, clamp((pct-i*step)*width, 0, 100)))
+	}
+	return b.String()
+}
+
+// cellGauge fills width cells in eighths, using partial glyphs for the last filled cell
+func cellGauge(width, pct int, full, empty string, partial []string) string {
+	eighths := pct * width * 8 / 100
+	var b strings.Builder
+	for i := 0; i < width; i++ {
+		switch cell := eighths - i*8; {
+		case cell >= 8:
+			b.WriteString(full)
+		case cell <= 0:
+			b.WriteString(empty)
+		default:
+			b.WriteString(partial[cell-1])
+		}
+	}
+	return b.String()
+}
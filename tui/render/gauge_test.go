@@ -0,0 +1,53 @@
+package render
+
+import (
+	"testing"
+
+	"statusline-config/config"
+)
+
+func TestGauge(t *testing.T) {
+	cfg := config.DefaultConfig()
+	moons := cfg.Icons.Moons
+	tests := []struct {
+		style string
+		pct   int
+		want  string
+	}{
+		{config.GaugeBlocks, 0, "░░░░░░░░"},
+		{config.GaugeBlocks, 50, "████░░░░"},
+		{config.GaugeBlocks, 55, "████▍░░░"},
+		{config.GaugeBlocks, 100, "████████"},
+		{config.GaugeBlocks, 130, "████████"},
+		{config.GaugeBraille, 50, "⣿⣿⡇⠀⠀"},
+		{config.GaugeASCII, 45, "[#####.....]"},
+		{config.GaugeASCII, -5, "[..........]"},
+		// Each moon covers a third of the window, phased by thresholds.moon_phases
+		{config.GaugeMoons, 50, moons[4] + moons[2] + moons[0]},
+		{config.GaugeMoons, 0, moons[0] + moons[0] + moons[0]},
+		// The last moon starts at 66%, as in statusline.sh: (73-66)*3 = 21
+		{config.GaugeMoons, 73, moons[4] + moons[4] + moons[1]},
+		{"unknown", 100, moons[4] + moons[4] + moons[4]},
+	}
+	for _, tt := range tests {
+		if got := Gauge(cfg, tt.style, tt.pct); got != tt.want {
+			t.Errorf("Gauge(%s, %d) = %q, want %q", tt.style, tt.pct, got, tt.want)
+		}
+	}
+}
+
+func TestGaugeWidths(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Display.Gauge.Widths = map[string]int{config.GaugeASCII: 4, config.GaugeMoons: 1, config.GaugeBlocks: 0}
+	moons := cfg.Icons.Moons
+
+	if got, want := Gauge(cfg, config.GaugeASCII, 45), "[##..]"; got != want {
+		t.Errorf("ascii of width 4 = %q, want %q", got, want)
+	}
+	if got, want := Gauge(cfg, config.GaugeMoons, 45), moons[2]; got != want {
+		t.Errorf("a single moon = %q, want %q", got, want)
+	}
+	if got := Gauge(cfg, config.GaugeBlocks, 50); got != "████░░░░" {
+		t.Errorf("a zero width falls back to the default: %q", got)
+	}
+}
@@ -0,0 +1,152 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+
+	"statusline-config/git"
+)
+
+// previewGit is the sample repository shown in the editor's preview
+var previewGit = &git.Status{Branch: "main", Commit: "4f2a9c1e", Ahead: 2, Staged: 1, Modified: 3, Untracked: 1, Stash: 1}
+
+// GitStatus returns the working tree status, read once per render and shared
+// by the git segments; it is nil outside a repository
+func (c *Context) GitStatus() *git.Status {
+	if c.Preview {
+		return previewGit
+	}
+	if !c.gitRead {
+		c.gitRead = true
+		if c.GitCache != nil {
+			c.gitStatus, _ = c.GitCache.Status(c.WorkDir)
+		} else {
+			c.gitStatus, _ = git.Read(c.WorkDir)
+		}
+	}
+	return c.gitStatus
+}
+
+// gitBranch returns the current branch, or the short SHA on a detached HEAD
+func gitBranch(ctx *Context) string {
+	status := ctx.GitStatus()
+	if status == nil {
+		return ""
+	}
+	if status.Detached() {
+		return status.ShortCommit()
+	}
+	return status.Branch
+}
+
+// renderGit shows the current branch, colored by whether the tree is clean
+func renderGit(ctx *Context) string {
+	status := ctx.GitStatus()
+	if status == nil {
+		return ""
+	}
+	icons, colors := ctx.Config.Icons, ctx.Config.Colors
+
+	name := status.Branch
+	if status.Detached() {
+		name = icons.GitDetached + status.ShortCommit()
+	}
+	if name == "" {
+		return ""
+	}
+
+	if status.Stale {
+		name += " " + icons.GitStale
+	}
+
+	if status.Dirty() {
+		return ctx.Paint(colors.GitDirty, icons.GitDirty+" "+name)
+	}
+	return ctx.Paint(colors.GitClean, icons.GitClean+" "+name)
+}
+
+// shortGit shows only the branch, still colored by whether the tree is clean
+func shortGit(ctx *Context) string {
+	status := ctx.GitStatus()
+	name := gitBranch(ctx)
+	if name == "" {
+		return ""
+	}
+	if status.Dirty() {
+		return ctx.Paint(ctx.Config.Colors.GitDirty, name)
+	}
+	return ctx.Paint(ctx.Config.Colors.GitClean, name)
+}
+
+// renderGitAheadBehind shows how far the branch has diverged from its upstream
+func renderGitAheadBehind(ctx *Context) string {
+	status := ctx.GitStatus()
+	if status == nil {
+		return ""
+	}
+	icons := ctx.Config.Icons
+	text := gitCount(icons.GitAhead, status.Ahead) + gitCount(icons.GitBehind, status.Behind)
+	return paintCounts(ctx, ctx.Config.Colors.Text, text)
+}
+
+// renderGitStaged shows the number of files staged for commit
+func renderGitStaged(ctx *Context) string {
+	status := ctx.GitStatus()
+	if status == nil {
+		return ""
+	}
+	return paintCounts(ctx, ctx.Config.Colors.GitClean, gitCount(ctx.Config.Icons.GitStaged, status.Staged))
+}
+
+// renderGitModified shows the number of files with unstaged changes or conflicts
+func renderGitModified(ctx *Context) string {
+	status := ctx.GitStatus()
+	if status == nil {
+		return ""
+	}
+	return paintCounts(ctx, ctx.Config.Colors.GitDirty, gitCount(ctx.Config.Icons.GitModified, status.Modified+status.Conflicts))
+}
+
+// renderGitUntracked shows the number of untracked files
+func renderGitUntracked(ctx *Context) string {
+	status := ctx.GitStatus()
+	if status == nil {
+		return ""
+	}
+	return paintCounts(ctx, ctx.Config.Colors.GitDirty, gitCount(ctx.Config.Icons.GitUntracked, status.Untracked))
+}
+
+// renderGitStash shows the number of stash entries
+func renderGitStash(ctx *Context) string {
+	status := ctx.GitStatus()
+	if status == nil {
+		return ""
+	}
+	return paintCounts(ctx, ctx.Config.Colors.Text, gitCount(ctx.Config.Icons.GitStash, status.Stash))
+}
+
+// renderGitOperation flags a rebase, merge, cherry-pick, revert or bisect in progress
+func renderGitOperation(ctx *Context) string {
+	status := ctx.GitStatus()
+	if status == nil || status.Operation == "" {
+		return ""
+	}
+	return ctx.Paint(ctx.Config.Colors.GitDirty, withIcon(ctx.Config.Icons.GitOperation, strings.ToUpper(status.Operation)))
+}
+
+// gitCount renders a count after its icon, or nothing when it is zero
+func gitCount(icon string, n int) string {
+	if n == 0 {
+		return ""
+	}
+	return icon + strconv.Itoa(n)
+}
+
+// paintCounts colors the counts from gitCount, leaving the segment out when
+// they are all zero
+func paintCounts(ctx *Context, value, counts string) string {
+	if counts == "" {
+		return ""
+	}
+	return ctx.Paint(value, counts)
+}
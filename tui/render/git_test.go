@@ -0,0 +1,65 @@
+package render
+
+import (
+	"testing"
+
+	"statusline-config/color"
+	"statusline-config/git"
+)
+
+// gitRow renders only the git segments for the given status
+func gitRow(t *testing.T, status *git.Status) string {
+	t.Helper()
+	ctx := testContext(t, `{}`)
+	ctx.Profile = color.ANSI
+	ctx.gitStatus, ctx.gitRead = status, true
+	ctx.Config.EnabledSections["git_stash"] = true
+
+	var segs []Registration
+	for _, r := range Registered() {
+		if r.Group == "git" {
+			segs = append(segs, r)
+		}
+	}
+	return renderRow(ctx, segs, " • ")
+}
+
+func TestGitCleanRepo(t *testing.T) {
+	ctx := testContext(t, `{}`)
+	ctx.Profile = color.ANSI
+	icons, colors := ctx.Config.Icons, ctx.Config.Colors
+
+	got := gitRow(t, &git.Status{Branch: "main", Commit: "4f2a9c1e"})
+	if want := ctx.Paint(colors.GitClean, icons.GitClean+" main"); got != want {
+		t.Errorf("clean repo = %q, want only the branch %q", got, want)
+	}
+}
+
+func TestGitDirtyRepo(t *testing.T) {
+	ctx := testContext(t, `{}`)
+	ctx.Profile = color.ANSI
+	icons, colors := ctx.Config.Icons, ctx.Config.Colors
+
+	got := gitRow(t, &git.Status{Branch: "main", Commit: "4f2a9c1e", Behind: 3, Staged: 1, Untracked: 2, Stash: 1})
+	want := ctx.Paint(colors.GitDirty, icons.GitDirty+" main") + " " +
+		ctx.Paint(colors.Text, icons.GitBehind+"3") + " " +
+		ctx.Paint(colors.GitClean, icons.GitStaged+"1") + " " +
+		ctx.Paint(colors.GitDirty, icons.GitUntracked+"2") + " " +
+		ctx.Paint(colors.Text, icons.GitStash+"1")
+	if got != want {
+		t.Errorf("dirty repo =\n%q, want\n%q", got, want)
+	}
+}
+
+func TestGitDetachedHead(t *testing.T) {
+	ctx := testContext(t, `{}`)
+	ctx.Profile = color.ANSI
+	icons, colors := ctx.Config.Icons, ctx.Config.Colors
+
+	got := gitRow(t, &git.Status{Commit: "4f2a9c1e0b7d", Operation: "rebase"})
+	want := ctx.Paint(colors.GitClean, icons.GitClean+" "+icons.GitDetached+"4f2a9c1") + " " +
+		ctx.Paint(colors.GitDirty, withIcon(icons.GitOperation, "REBASE"))
+	if got != want {
+		t.Errorf("detached rebase =\n%q, want\n%q", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package render
+
+import (
+	"strings"
+
+	"statusline-config/config"
+)
+
+// Fallback animations used when a mood has no emojis configured
+var (
+	fallbackPanic      = []string{"😰", "😱", "🆘", "😱"}
+	fallbackProductive = []string{"🔨", "⚒️", "🛠️", "⚒️"}
+	fallbackDeletion   = []string{"🧹", "✨", "🗑️", "✨"}
+	fallbackNight      = []string{"🦉", "💤", "🌙", "💤"}
+	fallbackMorning    = []string{"☀️", "🌅", "☕", "🌅"}
+	fallbackAfternoon  = []string{"💻", "⌨️", "🖱️", "⌨️"}
+	fallbackEvening    = []string{"🌆", "🌇", "🌃", "🌇"}
+)
+
+// renderMascot picks the mascot emoji for the current mood
+func renderMascot(ctx *Context) string {
+	mascot := ctx.Config.Mascot
+	added := ctx.Payload.Cost.TotalLinesAdded
+	removed := ctx.Payload.Cost.TotalLinesRemoved
+
+	// Context panic mode
+	if mascot.ContextPanic.Enabled && ctx.Percent() > mascot.ContextPanic.Threshold {
+		return pickState(ctx, mascot.ContextPanic, fallbackPanic, 500)
+	}
+
+	// Productive mode (lots of lines added)
+	if mascot.Productive.Enabled && added > mascot.Productive.Threshold {
+		return pickState(ctx, mascot.Productive, fallbackProductive, 400)
+	}
+
+	// Deletion mode
+	if mascot.Deletion.Enabled && removed > added && removed > mascot.Deletion.Threshold {
+		return pickState(ctx, mascot.Deletion, fallbackDeletion, 350)
+	}
+
+	// Time-based moods (default)
+	timeBased := mascot.TimeBased
+	if ctx.Preview {
+		return previewMascot(timeBased)
+	}
+	if !timeBased.Enabled {
+		return "🤖"
+	}
+
+	var emojis, fallback []string
+	switch hour := ctx.Now.Hour(); {
+	case hour < 6:
+		emojis, fallback = timeBased.Night, fallbackNight
+	case hour < 12:
+		emojis, fallback = timeBased.Morning, fallbackMorning
+	case hour < 18:
+		emojis, fallback = timeBased.Afternoon, fallbackAfternoon
+	default:
+		emojis, fallback = timeBased.Evening, fallbackEvening
+	}
+	if len(emojis) == 0 {
+		return fallback[animFrame(ctx, len(fallback), 600, true)]
+	}
+	return emojis[animFrame(ctx, len(emojis), timeBased.Speed, timeBased.Animate)]
+}
+
+// pickState returns the current frame of a mood, falling back to the built-in animation
+func pickState(ctx *Context, state config.MascotState, fallback []string, fallbackSpeed int) string {
+	if len(state.Emojis) == 0 {
+		return fallback[animFrame(ctx, len(fallback), fallbackSpeed, true)]
+	}
+	return state.Emojis[animFrame(ctx, len(state.Emojis), state.Speed, state.Animate)]
+}
+
+// animFrame returns the frame index for an animation of count frames
+func animFrame(ctx *Context, count, speed int, animate bool) int {
+	if count <= 0 {
+		return 0
+	}
+	if animate {
+		if speed <= 0 {
+			speed = 500
+		}
+		return int((ctx.Now.UnixMilli() / int64(speed)) % int64(count))
+	}
+	// Non-animated: change every ~10 seconds
+	return int((ctx.Now.Unix() / 10) % int64(count))
+}
+
+// previewMascot shows the afternoon animation sequence so frames can be compared at a glance
+func previewMascot(timeBased config.TimeBasedMood) string {
+	if !timeBased.Enabled || len(timeBased.Afternoon) == 0 {
+		return "💻→⌨️→🖱️→⌨️"
+	}
+	if timeBased.Animate && len(timeBased.Afternoon) > 1 {
+		return strings.Join(timeBased.Afternoon, "→")
+	}
+	return timeBased.Afternoon[0]
+}
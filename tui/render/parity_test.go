@@ -0,0 +1,206 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"statusline-config/color"
+	"statusline-config/config"
+	"statusline-config/payload"
+	"statusline-config/state"
+)
+
+// scriptPath is statusline.sh as it was before the native renderer replaced
+// it, kept here so later edits to the shipped script cannot move the target
+const scriptPath = "testdata/parity/statusline.sh"
+
+// parityFixture is a session rendered by both statusline.sh and Render. The
+// config sets the colors, moons and separator the script hard-codes, and a
+// fixed mascot since the script cannot turn sections off, so the two must
+// print the same line. Directory names stay within directory_max_length:
+// beyond it Render keeps the end of the name where the script cut it off.
+type parityFixture struct {
+	// Dir names the directory the session runs in
+	Dir     string          `json:"dir"`
+	Config  json.RawMessage `json:"config"`
+	Payload json.RawMessage `json:"payload"`
+	// WaitingFor, when set, puts the session in the waiting state that many seconds ago
+	WaitingFor int64 `json:"waiting_for"`
+	// Git, when set, makes the session directory a repository with one commit
+	// on branch main, plus these files left uncommitted
+	Git *struct {
+		Untracked []string `json:"untracked"`
+	} `json:"git"`
+}
+
+// loadParityFixture reads a fixture, filling the session directory into the payload
+func loadParityFixture(t *testing.T, path string) (fixture parityFixture, home, workDir string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+
+	root := t.TempDir()
+	home = filepath.Join(root, "home")
+	workDir = filepath.Join(root, fixture.Dir)
+	if err := os.MkdirAll(filepath.Join(home, ".claude"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".claude", config.ConfigFileName), fixture.Config, 0644); err != nil {
+		t.Fatal(err)
+	}
+	fixture.Payload = bytes.ReplaceAll(fixture.Payload, []byte("{{workdir}}"), []byte(workDir))
+
+	// Keep git from finding a repository above the temp dir
+	t.Setenv("GIT_CEILING_DIRECTORIES", root)
+	if fixture.Git != nil {
+		initRepo(t, workDir)
+		for _, name := range fixture.Git.Untracked {
+			if err := os.WriteFile(filepath.Join(workDir, name), []byte("new\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return fixture, home, workDir
+}
+
+// initRepo makes dir a git repository with one commit on main
+func initRepo(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("parity\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "README"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "Initial commit"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+}
+
+// stateStore returns the waiting state store in home, as the hooks write it
+func stateStore(home string) *state.Store {
+	return &state.Store{Dir: filepath.Join(home, ".claude", state.DirName)}
+}
+
+// setWaiting records the fixture's waiting state for its session, if it has one
+func setWaiting(t *testing.T, fixture parityFixture, home string) {
+	t.Helper()
+	if fixture.WaitingFor == 0 {
+		return
+	}
+	// The script reads the single global state file the waiting hooks used to write
+	legacy := fmt.Sprintf(`{"waiting": true, "type": "permission", "timestamp": %d}`, time.Now().Unix()-fixture.WaitingFor)
+	if err := os.WriteFile(filepath.Join(home, ".claude", ".statusline-state.json"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var session struct {
+		SessionID string `json:"session_id"`
+	}
+	json.Unmarshal(fixture.Payload, &session)
+	err := stateStore(home).Update(session.SessionID, func(e *state.Entry) error {
+		e.Waiting = true
+		e.Type = "permission"
+		e.Timestamp = time.Now().Unix() - fixture.WaitingFor
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runScript renders the fixture with statusline.sh
+func runScript(t *testing.T, fixture parityFixture, home, workDir string) string {
+	t.Helper()
+	setWaiting(t, fixture, home)
+	// Read the script here rather than in bash, so that go test reruns when it changes
+	script, err := os.ReadFile(scriptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("bash", "-c", string(script), "statusline.sh")
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "HOME="+home, "TERM=xterm")
+	cmd.Stdin = bytes.NewReader(fixture.Payload)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("statusline.sh: %v\n%s", err, stderr.String())
+	}
+	return strings.TrimSuffix(string(out), "\n")
+}
+
+// runRender renders the fixture with Render, as `lunar-editor render` would
+func runRender(t *testing.T, fixture parityFixture, home string) string {
+	t.Helper()
+	setWaiting(t, fixture, home)
+	cfg, err := config.LoadFromPath(filepath.Join(home, ".claude", config.ConfigFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := payload.Parse(fixture.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := &Context{
+		Config:  cfg,
+		Payload: p,
+		Now:     time.Now(),
+		WorkDir: WorkDir(p),
+		Profile: color.ANSI,
+		Store:   stateStore(home),
+	}
+	return Render(ctx)
+}
+
+// steady drops the blink attribute from the waiting indicator, which comes and
+// goes every second and so may differ between the two runs
+func steady(line string) string {
+	return strings.ReplaceAll(line, ansiBlink, ansiWaiting)
+}
+
+func TestRenderMatchesScript(t *testing.T) {
+	for _, tool := range []string{"bash", "jq", "git"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("statusline.sh needs %s", tool)
+		}
+	}
+
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "parity", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no parity fixtures")
+	}
+	for _, path := range fixtures {
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".json"), func(t *testing.T) {
+			fixture, home, workDir := loadParityFixture(t, path)
+			want := steady(runScript(t, fixture, home, workDir))
+			if got := steady(runRender(t, fixture, home)); got != want {
+				t.Errorf("render differs from statusline.sh\n got: %q\nwant: %q", got, want)
+			}
+		})
+	}
+}
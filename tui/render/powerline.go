@@ -0,0 +1,65 @@
+package render
+
+import (
+	"os"
+	"strings"
+
+	"statusline-config/color"
+	"statusline-config/config"
+)
+
+// Powerline separators from the Nerd Font private use area
+const (
+	powerlineArrow = "\ue0b0" // Between segments of different backgrounds
+	powerlineThin  = "\ue0b1" // Between segments sharing a background
+)
+
+// usePowerline reports whether the line is drawn in the powerline style. It
+// falls back to plain text without colors, and on the Linux console, whose
+// font has no powerline glyphs.
+func usePowerline(ctx *Context) bool {
+	return ctx.Config.Display.Style == config.DisplayPowerline &&
+		ctx.Profile != color.NoColor &&
+		os.Getenv("TERM") != "linux"
+}
+
+// background returns the powerline background of a segment
+func background(ctx *Context, seg Registration) color.Color {
+	value := ctx.Config.Colors.Field(seg.Background)
+	if value == nil {
+		return color.Color{}
+	}
+	c, _ := color.Parse(*value)
+	return c
+}
+
+// joinPowerline draws each part on its background, joined by arrows whose
+// colors blend the neighboring backgrounds. Parts of the same group share
+// one block.
+func joinPowerline(ctx *Context, parts []part) string {
+	if len(parts) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	var prev color.Color
+	for i, p := range parts {
+		bg := background(ctx, p.seg)
+		bgSeq := bg.Background(ctx.Profile)
+		switch {
+		case i == 0:
+			b.WriteString(bgSeq + " ")
+		case p.seg.Group != "" && p.seg.Group == parts[i-1].seg.Group:
+			b.WriteString(" ")
+		case bg == prev:
+			b.WriteString(" " + powerlineThin + " ")
+		default:
+			b.WriteString(" " + color.Reset + prev.Foreground(ctx.Profile) + bgSeq + powerlineArrow + color.Reset + bgSeq + " ")
+		}
+		// Segments reset their colors when they end; keep the background on
+		b.WriteString(strings.ReplaceAll(p.text, color.Reset, color.Reset+bgSeq))
+		prev = bg
+	}
+	b.WriteString(" " + color.Reset + prev.Foreground(ctx.Profile) + powerlineArrow + color.Reset)
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package render
+
+import (
+	"testing"
+
+	"statusline-config/color"
+	"statusline-config/config"
+)
+
+// powerlineContext returns a context drawing the powerline style in 256 colors
+func powerlineContext(t *testing.T) *Context {
+	t.Helper()
+	t.Setenv("TERM", "xterm-256color")
+	ctx := testContext(t, `{}`)
+	ctx.Profile = color.ANSI256
+	ctx.Config.Display.Style = config.DisplayPowerline
+	ctx.Config.Colors.GitBg = "238"
+	ctx.Config.Colors.ModelBg = "53"
+	ctx.Config.Colors.DirectoryBg = "238"
+	return ctx
+}
+
+// bg parses a config color value
+func bg(t *testing.T, value string) color.Color {
+	t.Helper()
+	c, err := color.Parse(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestJoinPowerline(t *testing.T) {
+	ctx := powerlineContext(t)
+	git, model, dir := bg(t, "238"), bg(t, "53"), bg(t, "238")
+	p := ctx.Profile
+	parts := []part{
+		{seg: Registration{Segment: fixedSegment{"git", "main"}, Group: "git", Background: "git_bg"}, text: "main"},
+		{seg: Registration{Segment: fixedSegment{"git_ahead", "↑1"}, Group: "git", Background: "git_bg"}, text: "↑1"},
+		{seg: Registration{Segment: fixedSegment{"model", "Opus"}, Background: "model_bg"}, text: "\033[36mOpus" + color.Reset},
+		{seg: Registration{Segment: fixedSegment{"dir", "~/src"}, Background: "directory_bg"}, text: "~/src"},
+	}
+
+	want := git.Background(p) + " main ↑1" + // One block for the git group
+		" " + color.Reset + git.Foreground(p) + model.Background(p) + powerlineArrow + color.Reset + model.Background(p) + " " +
+		"\033[36mOpus" + color.Reset + model.Background(p) + // The segment's reset keeps the background
+		" " + color.Reset + model.Foreground(p) + dir.Background(p) + powerlineArrow + color.Reset + dir.Background(p) + " ~/src" +
+		" " + color.Reset + dir.Foreground(p) + powerlineArrow + color.Reset
+	if got := joinPowerline(ctx, parts); got != want {
+		t.Errorf("joinPowerline =\n%q\nwant\n%q", got, want)
+	}
+
+	// Neighbors on the same background are split by a thin arrow
+	same := []part{parts[0], parts[3]}
+	want = git.Background(p) + " main " + powerlineThin + " ~/src" + " " + color.Reset + dir.Foreground(p) + powerlineArrow + color.Reset
+	if got := joinPowerline(ctx, same); got != want {
+		t.Errorf("same background =\n%q\nwant\n%q", got, want)
+	}
+
+	if got := joinPowerline(ctx, nil); got != "" {
+		t.Errorf("no parts = %q, want nothing", got)
+	}
+}
+
+func TestPowerlineFallsBack(t *testing.T) {
+	parts := []part{
+		{seg: Registration{Segment: fixedSegment{"model", "Opus"}}, text: "Opus"},
+		{seg: Registration{Segment: fixedSegment{"dir", "~/src"}}, text: "~/src"},
+	}
+
+	ctx := powerlineContext(t)
+	ctx.Profile = color.NoColor
+	if got := join(ctx, parts, " | "); got != "Opus | ~/src" {
+		t.Errorf("without color: %q, want the plain line", got)
+	}
+
+	ctx = powerlineContext(t)
+	t.Setenv("TERM", "linux")
+	if got := join(ctx, parts, " | "); got != "Opus | ~/src" {
+		t.Errorf("on the Linux console: %q, want the plain line", got)
+	}
+}
@@ -0,0 +1,262 @@
+// Package render builds the statusline from the JSON document Claude Code
+// writes to the statusline command's stdin.
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"statusline-config/color"
+	"statusline-config/config"
+	"statusline-config/git"
+	"statusline-config/notify"
+	"statusline-config/payload"
+	"statusline-config/state"
+)
+
+// Context carries everything a section needs to render itself
+type Context struct {
+	Config  *config.Config
+	Payload *payload.Payload
+	Now     time.Time
+	WorkDir string
+	Profile color.Profile
+	// Store holds the per-session waiting state; nil disables the waiting indicator
+	Store *state.Store
+	// GitCache reuses git status between renders; nil runs git every time
+	GitCache *git.Cache
+	// Preview renders sample data instead of touching git or the state file
+	Preview bool
+	// MaxWidth is the number of terminal cells the line must fit in; 0 means no limit
+	MaxWidth int
+
+	gitStatus *git.Status
+	gitRead   bool
+}
+
+// previewPayload is the sample session shown in the editor's preview
+const previewPayload = `{
+  "model": {"id": "claude-sonnet-4", "display_name": "Sonnet"},
+  "workspace": {"current_dir": "/home/user/project", "project_dir": "/home/user/project"},
+  "context_window": {"used_percentage": 45, "total_input_tokens": 12400},
+  "cost": {"total_cost_usd": 1.84, "total_duration_ms": 4320000, "total_api_duration_ms": 1512000,
+           "total_lines_added": 123, "total_lines_removed": 45}
+}`
+
+// PreviewContext returns a context rendering sample data with the given config
+func PreviewContext(cfg *config.Config) *Context {
+	p, _ := payload.Parse([]byte(previewPayload))
+	return &Context{
+		Config:   cfg,
+		Payload:  p,
+		Now:      time.Now(),
+		WorkDir:  p.Workspace.CurrentDir,
+		Profile:  color.DetectProfile(),
+		Preview:  true,
+		MaxWidth: cfg.Display.MaxWidth,
+	}
+}
+
+// Paint colors text with the config color value, degraded to the terminal's profile
+func (c *Context) Paint(value, text string) string {
+	return color.Paint(c.Profile, value, text)
+}
+
+// Percent returns the context usage as a whole percentage, truncated like the script did
+func (c *Context) Percent() int {
+	return int(c.Payload.ContextWindow.UsedPercentage)
+}
+
+// WorkDir returns the directory the session is in. Claude Code may run the
+// command from elsewhere; the payload knows where the session is.
+func WorkDir(p *payload.Payload) string {
+	workDir := p.Workspace.CurrentDir
+	if !p.Has("workspace.current_dir") || workDir == "" {
+		workDir = p.Cwd
+	}
+	if workDir == "" {
+		workDir, _ = os.Getwd()
+	}
+	return workDir
+}
+
+// Run writes the statusline for payload p to w
+func Run(cfg *config.Config, p *payload.Payload, w io.Writer) error {
+	workDir := WorkDir(p)
+	store, _ := state.DefaultStore()
+	ctx := &Context{
+		Config:   cfg,
+		Payload:  p,
+		Now:      time.Now(),
+		WorkDir:  workDir,
+		Profile:  color.DetectProfile(),
+		Store:    store,
+		GitCache: gitCache(cfg),
+		MaxWidth: maxWidth(cfg),
+	}
+
+	_, err := fmt.Fprintln(w, Render(ctx))
+
+	// Claude Code captures stdout, so the title and bell go straight to the terminal
+	env := notify.Env{
+		Runner:   notify.ExecRunner{},
+		Tmux:     os.Getenv("TMUX"),
+		TmuxPane: os.Getenv("TMUX_PANE"),
+	}
+	if tty := notify.OpenTTY(); tty != nil {
+		defer tty.Close()
+		env.TTY = tty
+	}
+	setTitle(ctx, env)
+	notifyContext(ctx, env)
+	notifyBudget(ctx, env)
+
+	// Sweep state left behind by sessions that ended long ago
+	if store != nil {
+		store.GC(ctx.Now)
+	}
+	return err
+}
+
+// part is a rendered segment waiting to be joined into the line
+type part struct {
+	seg      Registration
+	text     string
+	priority int
+	short    bool // text is already the short form
+}
+
+// Render composes the statusline for the given context, from Display.Format
+// when it is set and valid. Otherwise each row of Display.Lines, or a single
+// row of all sections, joins its enabled segments, shortening and then
+// dropping the lowest-priority ones until the row fits ctx.MaxWidth.
+func Render(ctx *Context) string {
+	if format := ctx.Config.Display.Format; format != "" {
+		if tmpl, err := ParseFormat(format); err == nil {
+			if line, err := RenderFormat(ctx, tmpl); err == nil {
+				return line
+			}
+		}
+	}
+
+	display := ctx.Config.Display
+	if len(display.Lines) == 0 {
+		return renderRow(ctx, Ordered(ctx.Config), display.Separator)
+	}
+	var rows []string
+	for _, line := range display.Lines {
+		var segs []Registration
+		for _, name := range line.Sections {
+			if seg, ok := Lookup(name); ok {
+				segs = append(segs, seg)
+			}
+		}
+		separator := line.Separator
+		if separator == "" {
+			separator = display.Separator
+		}
+		if row := renderRow(ctx, segs, separator); row != "" {
+			rows = append(rows, row)
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// renderRow joins the enabled segments of one row, fitted to ctx.MaxWidth
+func renderRow(ctx *Context, segs []Registration, separator string) string {
+	var parts []part
+	for _, seg := range segs {
+		if !seg.Enabled(ctx.Config) {
+			continue
+		}
+		if text := seg.Render(ctx); text != "" {
+			parts = append(parts, part{seg: seg, text: text, priority: seg.PriorityOf(ctx.Config)})
+		}
+	}
+
+	line := join(ctx, parts, separator)
+	for ctx.MaxWidth > 0 && len(parts) > 0 && Width(line) > ctx.MaxWidth {
+		// Later segments go first among equals, as they are the least prominent
+		victim := len(parts) - 1
+		for i := len(parts) - 2; i >= 0; i-- {
+			if parts[i].priority < parts[victim].priority {
+				victim = i
+			}
+		}
+		p := &parts[victim]
+		if !p.short && p.seg.Short != nil {
+			p.short = true
+			if short := p.seg.Short(ctx); short != "" && Width(short) < Width(p.text) {
+				p.text = short
+				line = join(ctx, parts, separator)
+				continue
+			}
+		}
+		parts = append(parts[:victim], parts[victim+1:]...)
+		line = join(ctx, parts, separator)
+	}
+	return line
+}
+
+// join puts the parts together with separator, or a space between
+// consecutive parts of the same group, unless they are drawn as powerline
+func join(ctx *Context, parts []part, separator string) string {
+	if usePowerline(ctx) {
+		return joinPowerline(ctx, parts)
+	}
+	if separator == "" {
+		separator = " │ "
+	}
+
+	var b strings.Builder
+	for i, p := range parts {
+		if i > 0 {
+			if p.seg.Group != "" && p.seg.Group == parts[i-1].seg.Group {
+				b.WriteString(" ")
+			} else {
+				b.WriteString(separator)
+			}
+		}
+		b.WriteString(p.text)
+	}
+	return b.String()
+}
+
+// maxWidth returns the width the line must fit: the smaller of $COLUMNS and
+// cfg.Display.MaxWidth, whichever are set
+func maxWidth(cfg *config.Config) int {
+	width := cfg.Display.MaxWidth
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		if width <= 0 || columns < width {
+			width = columns
+		}
+	}
+	return width
+}
+
+// gitCache returns the on-disk git cache configured by cfg, refreshing slow
+// repositories in a background `git-refresh` process
+func gitCache(cfg *config.Config) *git.Cache {
+	cache, err := git.DefaultCache()
+	if err != nil {
+		return nil
+	}
+	cache.TTL = time.Duration(cfg.Git.CacheTTL) * time.Second
+	cache.Timeout = time.Duration(cfg.Git.TimeoutMs) * time.Millisecond
+	cache.Refresh = func(dir string) {
+		exe, err := os.Executable()
+		if err != nil {
+			return
+		}
+		cmd := exec.Command(exe, "git-refresh", dir)
+		if cmd.Start() == nil {
+			go cmd.Wait()
+		}
+	}
+	return cache
+}
@@ -0,0 +1,41 @@
+package render
+
+import (
+	"testing"
+
+	"statusline-config/config"
+)
+
+func TestRenderLines(t *testing.T) {
+	ctx := testContext(t, `{"model": {"display_name": "Opus"}, "context_window": {"used_percentage": 45, "total_input_tokens": 12400}}`)
+	ctx.Config.Display.Separator = " | "
+	ctx.Config.Display.Lines = []config.Line{
+		{Sections: []string{"model", "weather", "percentage"}},
+		{Sections: []string{"lines"}}, // Off by default, so the row is left out
+		{Sections: []string{"percentage", "model"}, Separator: " / "},
+	}
+
+	model, _ := Lookup("model")
+	percentage, _ := Lookup("percentage")
+	m, p := model.Render(ctx), percentage.Render(ctx)
+	want := m + " | " + p + "\n" + p + " / " + m
+	if got := Render(ctx); got != want {
+		t.Errorf("Render =\n%q, want\n%q", got, want)
+	}
+}
+
+func TestRenderSkipsDisabledSections(t *testing.T) {
+	ctx := testContext(t, `{"model": {"display_name": "Opus"}, "context_window": {"used_percentage": 45, "total_input_tokens": 12400}}`)
+	for _, reg := range Registered() {
+		ctx.Config.EnabledSections[reg.Name()] = false
+	}
+	ctx.Config.EnabledSections["model"] = true
+	ctx.Config.EnabledSections["percentage"] = true
+
+	model, _ := Lookup("model")
+	percentage, _ := Lookup("percentage")
+	want := model.Render(ctx) + " │ " + percentage.Render(ctx)
+	if got := Render(ctx); got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,151 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+
+	"statusline-config/color"
+	"statusline-config/config"
+	"statusline-config/state"
+)
+
+// ANSI escape sequences for the waiting indicator
+const (
+	ansiWaiting = "\033[1;33m"
+	ansiBlink   = "\033[1;33;5m"
+)
+
+func init() {
+	Register(Registration{Segment: section{"waiting_indicator", renderWaiting}, Label: "Waiting Indicator", Description: "Show alert when Claude needs your input", Background: "waiting_bg", Priority: 100, Short: shortWaiting})
+	Register(Registration{Segment: section{"git", renderGit}, Label: "Git Branch", Description: "Show current git branch and status", Group: "git", Background: "git_bg", Priority: 60, Short: shortGit})
+	Register(Registration{Segment: section{"git_ahead_behind", renderGitAheadBehind}, Label: "Git Ahead/Behind", Description: "Show commits ahead of and behind the upstream", Group: "git", Background: "git_bg", Priority: 25})
+	Register(Registration{Segment: section{"git_staged", renderGitStaged}, Label: "Git Staged", Description: "Show the number of staged files", Group: "git", Background: "git_bg", Priority: 20})
+	Register(Registration{Segment: section{"git_modified", renderGitModified}, Label: "Git Modified", Description: "Show the number of modified and conflicted files", Group: "git", Background: "git_bg", Priority: 20})
+	Register(Registration{Segment: section{"git_untracked", renderGitUntracked}, Label: "Git Untracked", Description: "Show the number of untracked files", Group: "git", Background: "git_bg", Priority: 15})
+	Register(Registration{Segment: section{"git_stash", renderGitStash}, Label: "Git Stash", Description: "Show the number of stash entries", Group: "git", Background: "git_bg", Priority: 10})
+	Register(Registration{Segment: section{"git_operation", renderGitOperation}, Label: "Git Operation", Description: "Show a rebase, merge, cherry-pick or bisect in progress", Group: "git", Background: "git_bg", Priority: 55})
+	Register(Registration{Segment: section{"directory", renderDirectory}, Label: "Directory", Description: "Show the current directory in the configured style", Background: "directory_bg", Priority: 50, Short: shortDirectory})
+	Register(Registration{Segment: section{"model", renderModel}, Label: "Model Name", Description: "Show Claude model in use", Background: "model_bg", Priority: 90})
+	Register(Registration{Segment: section{"context_moons", renderGauge}, Label: "Context Gauge", Description: "Moons or bar showing context usage", Group: "context", Background: "context_bg", Priority: 80})
+	Register(Registration{Segment: section{"token_count", renderTokens}, Label: "Token Count", Description: "Show token count (e.g., 12k)", Group: "context", Background: "context_bg", Priority: 30})
+	Register(Registration{Segment: section{"percentage", renderPercentage}, Label: "Percentage", Description: "Show context usage percentage", Group: "context", Background: "context_bg", Priority: 70})
+	Register(Registration{Segment: section{"budget", renderBudget}, Label: "Session Budget", Description: "Show session cost against the budget", Background: "session_bg", Priority: 65, Short: shortBudget})
+	Register(Registration{Segment: section{"cost", renderCost}, Label: "Session Cost", Description: "Show what the session has cost so far", Background: "session_bg", Priority: 40})
+	Register(Registration{Segment: section{"duration", renderDuration}, Label: "Duration", Description: "Show wall-clock time since the session started", Background: "session_bg", Priority: 35})
+	Register(Registration{Segment: section{"api_time", renderAPITime}, Label: "API Time", Description: "Show time spent waiting on the model and its share of wall time", Background: "session_bg", Priority: 20, Short: shortAPITime})
+	Register(Registration{Segment: section{"lines", renderLines}, Label: "Lines Changed", Description: "Show lines added and removed (e.g., +123/-45)", Background: "session_bg", Priority: 30})
+	Register(Registration{Segment: section{"mascot", renderMascot}, Label: "Mascot", Description: "Show reactive mascot emoji", Background: "mascot_bg", Priority: 5})
+}
+
+// Fallback moon phases used when the config has no moon icons
+var defaultMoons = []string{"●", "◐", "◑", "◕", "○"}
+
+// renderWaiting shows how long Claude has been waiting for input
+func renderWaiting(ctx *Context) string {
+	indicator := ctx.Config.WaitingIndicator
+	return waiting(ctx, func(waitTime string) string {
+		return fmt.Sprintf("%s %s (%s)", indicator.Icon, indicator.Text, waitTime)
+	})
+}
+
+// shortWaiting drops the indicator text and keeps the icon and wait time
+func shortWaiting(ctx *Context) string {
+	icon := ctx.Config.WaitingIndicator.Icon
+	return waiting(ctx, func(waitTime string) string {
+		return withIcon(icon, waitTime)
+	})
+}
+
+// waiting renders the indicator with format while the session is waiting for input
+func waiting(ctx *Context, format func(waitTime string) string) string {
+	if ctx.Preview {
+		return paintWaiting(ctx, format("12s"), false)
+	}
+
+	if ctx.Store == nil {
+		return ""
+	}
+	sessionID := ctx.Payload.SessionID
+	entry, err := ctx.Store.Get(sessionID)
+	if err != nil || !entry.Waiting {
+		return ""
+	}
+
+	now := ctx.Now.Unix()
+	waitSecs := now - entry.Timestamp
+
+	// Auto-clear stale waiting state (workaround for missing cancel hooks)
+	if waitSecs > int64(ctx.Config.WaitingIndicator.Timeout) {
+		ctx.Store.Update(sessionID, func(e *state.Entry) error {
+			e.Waiting = false
+			return nil
+		})
+		return ""
+	}
+
+	var waitTime string
+	if waitSecs < 60 {
+		waitTime = fmt.Sprintf("%ds", waitSecs)
+	} else {
+		waitTime = fmt.Sprintf("%dm", waitSecs/60)
+	}
+
+	// Blinking effect (alternates every second)
+	return paintWaiting(ctx, format(waitTime), ctx.Config.WaitingIndicator.Blink && now%2 == 0)
+}
+
+// paintWaiting renders the indicator in bold yellow, optionally blinking
+func paintWaiting(ctx *Context, text string, blink bool) string {
+	switch {
+	case ctx.Profile == color.NoColor:
+		return text
+	case blink:
+		return ansiBlink + text + color.Reset
+	default:
+		return ansiWaiting + text + color.Reset
+	}
+}
+
+// renderModel shows the display name of the model in use
+func renderModel(ctx *Context) string {
+	model := ctx.Payload.Model.DisplayName
+	if !ctx.Payload.Has("model.display_name") {
+		model = "?"
+	}
+	return ctx.Paint(ctx.Config.Colors.Model, model)
+}
+
+// renderTokens shows the input token count, in k above the configured threshold
+func renderTokens(ctx *Context) string {
+	tokens := ctx.Payload.ContextWindow.TotalInputTokens
+	text := strconv.Itoa(tokens)
+	if tokens > ctx.Config.Thresholds.TokenKFormat {
+		text = strconv.Itoa(tokens/1000) + "k"
+	}
+	return ctx.Paint(ctx.Config.Colors.Text, text)
+}
+
+// renderPercentage shows the context usage percentage
+func renderPercentage(ctx *Context) string {
+	return ctx.Paint(ctx.Config.Colors.Text, fmt.Sprintf("(%d%%)", ctx.Percent()))
+}
+
+// moonFor picks the moon phase for a single cell of the gauge
+func moonFor(cfg *config.Config, pct int) string {
+	phase := cfg.Thresholds.Phase(pct)
+	moons := cfg.Icons.Moons
+	if len(moons) == 0 {
+		moons = defaultMoons
+	}
+	return moons[clamp(phase, 0, len(moons)-1)]
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
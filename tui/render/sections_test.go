@@ -0,0 +1,86 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	"statusline-config/color"
+	"statusline-config/config"
+	"statusline-config/payload"
+	"statusline-config/state"
+)
+
+// testContext returns a context rendering p with the default config and no color
+func testContext(t *testing.T, p string) *Context {
+	t.Helper()
+	parsed, err := payload.Parse([]byte(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Context{
+		Config:  config.DefaultConfig(),
+		Payload: parsed,
+		Now:     time.Unix(1700000000, 0),
+		WorkDir: t.TempDir(),
+		Profile: color.NoColor,
+	}
+}
+
+func TestWaitingTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout int
+		waited  time.Duration
+		want    string
+	}{
+		{"fresh", 300, 45 * time.Second, "🔔 WAITING (45s)"},
+		{"minutes", 300, 125 * time.Second, "🔔 WAITING (2m)"},
+		{"stale", 300, 400 * time.Second, ""},
+		{"longer timeout", 600, 400 * time.Second, "🔔 WAITING (6m)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := testContext(t, `{"session_id": "s1"}`)
+			ctx.Config.WaitingIndicator.Timeout = tt.timeout
+			ctx.Config.WaitingIndicator.Blink = false
+			ctx.Store = &state.Store{Dir: t.TempDir()}
+			err := ctx.Store.Update("s1", func(e *state.Entry) error {
+				e.Waiting = true
+				e.Timestamp = ctx.Now.Add(-tt.waited).Unix()
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := renderWaiting(ctx); got != tt.want {
+				t.Errorf("renderWaiting() = %q, want %q", got, tt.want)
+			}
+			entry, err := ctx.Store.Get("s1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stale := tt.want == ""; entry.Waiting == stale {
+				t.Errorf("after render, waiting = %v, want %v", entry.Waiting, !stale)
+			}
+		})
+	}
+}
+
+func TestMoonPhases(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Thresholds.MoonPhases = []int{15, 40, 60, 85}
+	moons := cfg.Icons.Moons
+	tests := map[int]string{0: moons[0], 14: moons[0], 15: moons[1], 59: moons[2], 60: moons[3], 85: moons[4], 100: moons[4]}
+	for pct, want := range tests {
+		if got := moonFor(cfg, pct); got != want {
+			t.Errorf("moonFor(%d) = %q, want %q", pct, got, want)
+		}
+	}
+
+	// Fewer icons than phases keeps to the last icon
+	cfg.Icons.Moons = []string{"a", "b"}
+	if got := moonFor(cfg, 90); got != "b" {
+		t.Errorf("with two icons moonFor(90) = %q, want b", got)
+	}
+}
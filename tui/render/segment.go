@@ -0,0 +1,100 @@
+package render
+
+import (
+	"fmt"
+
+	"statusline-config/config"
+)
+
+// Segment is a single section of the statusline
+type Segment interface {
+	// Name is the segment's key in enabled_sections and section_order
+	Name() string
+	// Enabled reports whether the user has switched the segment on
+	Enabled(cfg *config.Config) bool
+	// Render returns the segment text, or "" to leave it out
+	Render(ctx *Context) string
+}
+
+// Registration is a segment together with the metadata the editor shows for it
+type Registration struct {
+	Segment
+	Label       string
+	Description string
+	// Group joins consecutive segments of the same group with a space instead of the separator
+	Group string
+	// Background is the key in Colors of the segment's background in the powerline style
+	Background string
+	// Priority decides what goes first when the line is too wide: lower is shortened or dropped sooner
+	Priority int
+	// Short renders a narrower form tried before the segment is dropped; nil if there is none
+	Short func(ctx *Context) string
+}
+
+// PriorityOf returns the segment's priority, as overridden by cfg.Display.Priorities
+func (r Registration) PriorityOf(cfg *config.Config) int {
+	if p, ok := cfg.Display.Priorities[r.Name()]; ok {
+		return p
+	}
+	return r.Priority
+}
+
+var registry []Registration
+
+// Register adds a segment to the registry; registration order is the default section order
+func Register(r Registration) {
+	if _, ok := Lookup(r.Name()); ok {
+		panic(fmt.Sprintf("render: segment %q registered twice", r.Name()))
+	}
+	registry = append(registry, r)
+}
+
+// Registered returns all segments in registration order
+func Registered() []Registration {
+	return append([]Registration(nil), registry...)
+}
+
+// Lookup finds a registered segment by name
+func Lookup(name string) (Registration, bool) {
+	for _, r := range registry {
+		if r.Name() == name {
+			return r, true
+		}
+	}
+	return Registration{}, false
+}
+
+// Ordered returns the registered segments in the order given by cfg.SectionOrder.
+// Unknown names are skipped and segments missing from the order keep their
+// registration order at the end.
+func Ordered(cfg *config.Config) []Registration {
+	ordered := make([]Registration, 0, len(registry))
+	seen := map[string]bool{}
+	for _, name := range cfg.SectionOrder {
+		if r, ok := Lookup(name); ok && !seen[name] {
+			ordered = append(ordered, r)
+			seen[name] = true
+		}
+	}
+	for _, r := range registry {
+		if !seen[r.Name()] {
+			ordered = append(ordered, r)
+		}
+	}
+	return ordered
+}
+
+// section adapts a render function to the Segment interface, using the
+// enabled_sections flag of the same name
+type section struct {
+	name   string
+	render func(ctx *Context) string
+}
+
+func (s section) Name() string { return s.name }
+
+func (s section) Enabled(cfg *config.Config) bool {
+	return cfg.EnabledSections.Enabled(s.name)
+}
+
+func (s section) Render(ctx *Context) string { return s.render(ctx) }
@@ -0,0 +1,55 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+
+	"statusline-config/config"
+)
+
+func TestEnabledDefaults(t *testing.T) {
+	cfg := config.DefaultConfig()
+	for _, r := range Registered() {
+		if _, ok := cfg.EnabledSections[r.Name()]; !ok {
+			t.Errorf("DefaultConfig has no default for segment %s", r.Name())
+		}
+	}
+
+	cfg, err := config.Parse([]byte(`{"version": "2.0", "enabled_sections": {"cost": true, "git": false}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(cfg.EnabledSections, "mascot")
+	tests := map[string]bool{
+		"cost":   true,  // Switched on by the file
+		"git":    false, // Switched off by the file
+		"model":  true,  // Not in the file, default on
+		"lines":  false, // Not in the file, default off
+		"mascot": true,  // Missing from the map, falls back to its default
+	}
+	for name, want := range tests {
+		seg, ok := Lookup(name)
+		if !ok {
+			t.Fatalf("segment %s is not registered", name)
+		}
+		if got := seg.Enabled(cfg); got != want {
+			t.Errorf("%s enabled = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestOrdered(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SectionOrder = []string{"model", "nonsense", "git", "model"}
+
+	var names []string
+	for _, r := range Ordered(cfg) {
+		names = append(names, r.Name())
+	}
+	if len(names) != len(Registered()) {
+		t.Fatalf("Ordered returned %d segments, want all %d", len(names), len(Registered()))
+	}
+	if got := names[:3]; !reflect.DeepEqual(got, []string{"model", "git", "waiting_indicator"}) {
+		t.Errorf("Ordered starts with %v, want model, git, then the rest in registration order", got)
+	}
+}
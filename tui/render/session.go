@@ -0,0 +1,112 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// withIcon prefixes text with an icon, if one is configured
+func withIcon(icon, text string) string {
+	if icon == "" {
+		return text
+	}
+	return icon + " " + text
+}
+
+// renderCost shows the session cost in USD with the configured precision
+func renderCost(ctx *Context) string {
+	if !ctx.Payload.Has("cost.total_cost_usd") {
+		return ""
+	}
+	precision := clamp(ctx.Config.Display.CostPrecision, 0, 6)
+	text := "$" + strconv.FormatFloat(ctx.Payload.Cost.TotalCostUSD, 'f', precision, 64)
+	return ctx.Paint(ctx.Config.Colors.Text, withIcon(ctx.Config.Icons.Cost, text))
+}
+
+// renderBudget shows the session cost against the budget, colored by how much of it is spent
+func renderBudget(ctx *Context) string {
+	budget := ctx.Config.Budget
+	if budget.SessionUSD <= 0 {
+		return ""
+	}
+	text := fmt.Sprintf("$%.2f/$%.2f", ctx.Payload.Cost.TotalCostUSD, budget.SessionUSD)
+	return ctx.Paint(budgetColor(ctx), withIcon(ctx.Config.Icons.Budget, text))
+}
+
+// shortBudget shows only the share of the budget spent, e.g. 42%
+func shortBudget(ctx *Context) string {
+	budget := ctx.Config.Budget
+	if budget.SessionUSD <= 0 {
+		return ""
+	}
+	text := fmt.Sprintf("%d%%", budget.Percent(ctx.Payload.Cost.TotalCostUSD))
+	return ctx.Paint(budgetColor(ctx), withIcon(ctx.Config.Icons.Budget, text))
+}
+
+// budgetColor picks the budget color for how much of it is spent
+func budgetColor(ctx *Context) string {
+	budget := ctx.Config.Budget
+	pct := budget.Percent(ctx.Payload.Cost.TotalCostUSD)
+	colors := ctx.Config.Colors
+	switch {
+	case pct >= 100:
+		return colors.BudgetOver
+	case budget.WarnAt > 0 && pct >= budget.WarnAt:
+		return colors.BudgetWarn
+	}
+	return colors.BudgetOK
+}
+
+// renderDuration shows the wall-clock session duration, e.g. 1h12m
+func renderDuration(ctx *Context) string {
+	if !ctx.Payload.Has("cost.total_duration_ms") {
+		return ""
+	}
+	text := formatDuration(ctx.Payload.Cost.TotalDurationMs)
+	return ctx.Paint(ctx.Config.Colors.Text, withIcon(ctx.Config.Icons.Duration, text))
+}
+
+// renderAPITime shows time spent waiting on the model and its share of the wall time
+func renderAPITime(ctx *Context) string {
+	if !ctx.Payload.Has("cost.total_api_duration_ms") {
+		return ""
+	}
+	cost := ctx.Payload.Cost
+	text := formatDuration(cost.TotalAPIDurationMs)
+	if cost.TotalDurationMs > 0 {
+		text += fmt.Sprintf(" (%d%%)", cost.TotalAPIDurationMs*100/cost.TotalDurationMs)
+	}
+	return ctx.Paint(ctx.Config.Colors.Text, withIcon(ctx.Config.Icons.APITime, text))
+}
+
+// shortAPITime shows the API time without its share of the wall time
+func shortAPITime(ctx *Context) string {
+	if !ctx.Payload.Has("cost.total_api_duration_ms") {
+		return ""
+	}
+	text := formatDuration(ctx.Payload.Cost.TotalAPIDurationMs)
+	return ctx.Paint(ctx.Config.Colors.Text, withIcon(ctx.Config.Icons.APITime, text))
+}
+
+// renderLines shows lines added and removed during the session
+func renderLines(ctx *Context) string {
+	if !ctx.Payload.Has("cost.total_lines_added") && !ctx.Payload.Has("cost.total_lines_removed") {
+		return ""
+	}
+	cost := ctx.Payload.Cost
+	text := fmt.Sprintf("+%d/-%d", cost.TotalLinesAdded, cost.TotalLinesRemoved)
+	return ctx.Paint(ctx.Config.Colors.Text, withIcon(ctx.Config.Icons.Lines, text))
+}
+
+// formatDuration renders milliseconds compactly: 45s, 12m or 1h12m
+func formatDuration(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,103 @@
+package render
+
+import "testing"
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[int64]string{
+		0:        "0s",
+		45900:    "45s",
+		60000:    "1m",
+		720000:   "12m",
+		3600000:  "1h00m",
+		4320000:  "1h12m",
+		90000000: "25h00m",
+	}
+	for ms, want := range tests {
+		if got := formatDuration(ms); got != want {
+			t.Errorf("formatDuration(%d) = %q, want %q", ms, got, want)
+		}
+	}
+}
+
+func TestSessionSegments(t *testing.T) {
+	ctx := testContext(t, `{"cost": {"total_cost_usd": 1.8372, "total_duration_ms": 4320000, "total_api_duration_ms": 1512000,
+	  "total_lines_added": 123, "total_lines_removed": 45}}`)
+	icons := ctx.Config.Icons
+
+	ctx.Config.Display.CostPrecision = 3
+	tests := []struct {
+		name   string
+		render func(*Context) string
+		want   string
+	}{
+		{"cost", renderCost, icons.Cost + " $1.837"},
+		{"duration", renderDuration, icons.Duration + " 1h12m"},
+		{"api_time", renderAPITime, icons.APITime + " 25m (35%)"},
+		{"api_time short", shortAPITime, icons.APITime + " 25m"},
+		{"lines", renderLines, icons.Lines + " +123/-45"},
+	}
+	for _, tt := range tests {
+		if got := tt.render(ctx); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	ctx.Config.Icons.Cost = ""
+	ctx.Config.Display.CostPrecision = 0
+	if got := renderCost(ctx); got != "$2" {
+		t.Errorf("cost without an icon or decimals = %q, want $2", got)
+	}
+}
+
+func TestSessionSegmentsWithoutData(t *testing.T) {
+	ctx := testContext(t, `{"cost": {"total_api_duration_ms": 30000}}`)
+	for name, render := range map[string]func(*Context) string{
+		"cost": renderCost, "duration": renderDuration, "lines": renderLines,
+	} {
+		if got := render(ctx); got != "" {
+			t.Errorf("%s without its payload field = %q, want nothing", name, got)
+		}
+	}
+	// Without the wall time there is no share to show
+	if got, want := renderAPITime(ctx), ctx.Config.Icons.APITime+" 30s"; got != want {
+		t.Errorf("api_time = %q, want %q", got, want)
+	}
+}
+
+func TestBudget(t *testing.T) {
+	ctx := testContext(t, `{"cost": {"total_cost_usd": 4.2}}`)
+	ctx.Config.Budget.WarnAt = 80
+	if got := renderBudget(ctx); got != "" {
+		t.Errorf("without a budget = %q, want nothing", got)
+	}
+
+	colors := ctx.Config.Colors
+	tests := []struct {
+		budget      float64
+		text, short string
+		color       string
+	}{
+		{10, "$4.20/$10.00", "42%", colors.BudgetOK},
+		{5, "$4.20/$5.00", "84%", colors.BudgetWarn},
+		{4.2, "$4.20/$4.20", "100%", colors.BudgetOver},
+		{2, "$4.20/$2.00", "210%", colors.BudgetOver},
+	}
+	for _, tt := range tests {
+		ctx.Config.Budget.SessionUSD = tt.budget
+		if got, want := renderBudget(ctx), ctx.Config.Icons.Budget+" "+tt.text; got != want {
+			t.Errorf("budget $%g = %q, want %q", tt.budget, got, want)
+		}
+		if got, want := shortBudget(ctx), ctx.Config.Icons.Budget+" "+tt.short; got != want {
+			t.Errorf("short budget $%g = %q, want %q", tt.budget, got, want)
+		}
+		if got := budgetColor(ctx); got != tt.color {
+			t.Errorf("budget $%g colored %q, want %q", tt.budget, got, tt.color)
+		}
+	}
+
+	// Without warn_at the budget only turns when it is spent
+	ctx.Config.Budget.SessionUSD, ctx.Config.Budget.WarnAt = 5, 0
+	if got := budgetColor(ctx); got != colors.BudgetOK {
+		t.Errorf("at 84%% without warn_at colored %q, want %q", got, colors.BudgetOK)
+	}
+}
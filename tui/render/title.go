@@ -0,0 +1,69 @@
+package render
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"statusline-config/notify"
+)
+
+// defaultPanicPrefix marks the title when context usage crosses the threshold
+const defaultPanicPrefix = "⚠ "
+
+// titleSeparator joins the parts of a title built from the Show* flags
+const titleSeparator = " · "
+
+// setTitle sets the terminal title when titles are on and there is a
+// terminal to set it on. The title is built only then: a branch in it costs
+// a git lookup.
+func setTitle(ctx *Context, env notify.Env) {
+	if !ctx.Config.Notifications.TerminalTitle.Enabled || env.TTY == nil {
+		return
+	}
+	if title := Title(ctx); title != "" {
+		notify.SetTitle(env.TTY, title)
+	}
+}
+
+// Title builds the terminal title from the terminal_title settings
+func Title(ctx *Context) string {
+	cfg := ctx.Config.Notifications.TerminalTitle
+
+	var title string
+	if cfg.Template != "" {
+		var branch string
+		if strings.Contains(cfg.Template, "{branch}") {
+			branch = gitBranch(ctx)
+		}
+		title = strings.NewReplacer(
+			"{model}", ctx.Payload.Model.DisplayName,
+			"{context}", strconv.Itoa(ctx.Percent())+"%",
+			"{branch}", branch,
+			"{dir}", filepath.Base(ctx.WorkDir),
+		).Replace(cfg.Template)
+	} else {
+		var parts []string
+		if cfg.ShowModel && ctx.Payload.Model.DisplayName != "" {
+			parts = append(parts, ctx.Payload.Model.DisplayName)
+		}
+		if cfg.ShowContext {
+			parts = append(parts, strconv.Itoa(ctx.Percent())+"%")
+		}
+		if cfg.ShowBranch {
+			if branch := gitBranch(ctx); branch != "" {
+				parts = append(parts, branch)
+			}
+		}
+		title = strings.Join(parts, titleSeparator)
+	}
+
+	if cfg.AlertOnPanic && ctx.Percent() >= cfg.ContextThreshold {
+		prefix := cfg.PanicPrefix
+		if prefix == "" {
+			prefix = defaultPanicPrefix
+		}
+		title = prefix + title
+	}
+	return title
+}
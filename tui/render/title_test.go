@@ -0,0 +1,84 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"statusline-config/config"
+	"statusline-config/git"
+	"statusline-config/notify"
+)
+
+func TestTitle(t *testing.T) {
+	base := config.TerminalTitleConfig{Enabled: true, ContextThreshold: 80}
+	tests := []struct {
+		name string
+		edit func(*config.TerminalTitleConfig)
+		pct  string
+		want string
+	}{
+		{"model", func(c *config.TerminalTitleConfig) { c.ShowModel = true }, "45", "Opus"},
+		{"all parts", func(c *config.TerminalTitleConfig) {
+			c.ShowModel, c.ShowContext, c.ShowBranch = true, true, true
+		}, "45", "Opus · 45% · main"},
+		{"template", func(c *config.TerminalTitleConfig) {
+			c.ShowModel = true
+			c.Template = "{dir} [{branch}] {context}"
+		}, "45", "web [main] 45%"},
+		{"below the threshold", func(c *config.TerminalTitleConfig) {
+			c.ShowContext, c.AlertOnPanic = true, true
+		}, "79", "79%"},
+		{"default panic prefix", func(c *config.TerminalTitleConfig) {
+			c.ShowContext, c.AlertOnPanic = true, true
+		}, "80", "⚠ 80%"},
+		{"panic prefix", func(c *config.TerminalTitleConfig) {
+			c.ShowModel, c.AlertOnPanic, c.PanicPrefix = true, true, "!! "
+		}, "91", "!! Opus"},
+	}
+	for _, tt := range tests {
+		ctx := testContext(t, `{"model": {"display_name": "Opus"}, "context_window": {"used_percentage": `+tt.pct+`}}`)
+		ctx.WorkDir = "/home/u/src/web"
+		ctx.gitStatus, ctx.gitRead = &git.Status{Branch: "main", Commit: "4f2a9c1e"}, true
+		cfg := base
+		tt.edit(&cfg)
+		ctx.Config.Notifications.TerminalTitle = cfg
+
+		if got := Title(ctx); got != tt.want {
+			t.Errorf("%s: Title = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTitleOutsideRepository(t *testing.T) {
+	ctx := testContext(t, `{"model": {"display_name": "Opus"}}`)
+	ctx.gitRead = true
+	ctx.Config.Notifications.TerminalTitle = config.TerminalTitleConfig{ShowModel: true, ShowBranch: true, ContextThreshold: 80}
+	if got := Title(ctx); got != "Opus" {
+		t.Errorf("Title = %q, want the model without a branch", got)
+	}
+}
+
+func TestSetTitle(t *testing.T) {
+	ctx := testContext(t, `{"model": {"display_name": "Opus"}}`)
+	ctx.Config.Notifications.TerminalTitle = config.TerminalTitleConfig{ShowModel: true, ShowBranch: true, ContextThreshold: 80}
+	var tty bytes.Buffer
+	setTitle(ctx, notify.Env{TTY: &tty})
+	if tty.Len() != 0 || ctx.gitRead {
+		t.Errorf("with titles off: wrote %q, read git %v; want neither", tty.String(), ctx.gitRead)
+	}
+
+	ctx.Config.Notifications.TerminalTitle.Enabled = true
+	ctx.gitStatus, ctx.gitRead = &git.Status{Branch: "main"}, true
+	setTitle(ctx, notify.Env{TTY: &tty})
+	if want := "\033]0;Opus · main\a"; tty.String() != want {
+		t.Errorf("with titles on: wrote %q, want %q", tty.String(), want)
+	}
+}
+
+func TestTitleTemplateWithoutBranch(t *testing.T) {
+	ctx := testContext(t, `{"model": {"display_name": "Opus"}}`)
+	ctx.Config.Notifications.TerminalTitle = config.TerminalTitleConfig{Template: "{model}", ContextThreshold: 80}
+	if got := Title(ctx); got != "Opus" || ctx.gitRead {
+		t.Errorf("Title = %q, read git %v; want the model without a git lookup", got, ctx.gitRead)
+	}
+}
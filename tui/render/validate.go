@@ -0,0 +1,41 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+
+	"statusline-config/config"
+)
+
+// Validate extends cfg.Validate with the checks that need the renderer:
+// section names must be registered segments and display.format must run
+func Validate(cfg *config.Config) []config.FieldError {
+	errs := cfg.Validate()
+	unknown := func(path, name string) {
+		if _, ok := Lookup(name); !ok {
+			errs = append(errs, config.FieldError{Path: path, Message: fmt.Sprintf("unknown section %q", name)})
+		}
+	}
+
+	for i, name := range cfg.SectionOrder {
+		unknown(fmt.Sprintf("section_order[%d]", i), name)
+	}
+	for i, line := range cfg.Display.Lines {
+		for j, name := range line.Sections {
+			unknown(fmt.Sprintf("display.lines[%d].sections[%d]", i, j), name)
+		}
+	}
+	names := make([]string, 0, len(cfg.Display.Priorities))
+	for name := range cfg.Display.Priorities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		unknown("display.priorities."+name, name)
+	}
+
+	if err := ValidateFormat(PreviewContext(cfg), cfg.Display.Format); err != nil {
+		errs = append(errs, config.FieldError{Path: "display.format", Message: err.Error()})
+	}
+	return errs
+}
@@ -0,0 +1,37 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+
+	"statusline-config/config"
+)
+
+func TestValidateSectionNames(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SectionOrder = []string{"model", "weather"}
+	cfg.Display.Lines = []config.Line{{Sections: []string{"git", "gti"}}}
+	cfg.Display.Priorities = map[string]int{"model": 1, "zeta": 2, "alpha": 3}
+	cfg.Display.Format = "{{.Model"
+
+	var paths []string
+	for _, err := range Validate(cfg) {
+		paths = append(paths, err.Path)
+	}
+	want := []string{
+		"section_order[1]",
+		"display.lines[0].sections[1]",
+		"display.priorities.alpha",
+		"display.priorities.zeta",
+		"display.format",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("errors at %q, want %q", paths, want)
+	}
+}
+
+func TestValidateDefaultConfig(t *testing.T) {
+	if errs := Validate(config.DefaultConfig()); len(errs) != 0 {
+		t.Errorf("Validate(DefaultConfig()) = %v, want no errors", errs)
+	}
+}
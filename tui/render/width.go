@@ -0,0 +1,129 @@
+package render
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+
+	"statusline-config/color"
+)
+
+// Code points that change how the preceding character is drawn
+const (
+	variationText  = '\uFE0E' // VS15: show the preceding character as text
+	variationEmoji = '\uFE0F' // VS16: show the preceding character as a 2-cell emoji
+	zeroWidthJoin  = '\u200D' // Joins emoji into a single glyph
+)
+
+// Width returns the number of terminal cells s occupies. ANSI escape
+// sequences take no space, emoji take two cells and variation selectors none.
+func Width(s string) int {
+	runes := []rune(stripANSI(s))
+	width := 0
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == variationText || r == variationEmoji:
+			continue
+		case r == zeroWidthJoin:
+			// The joined emoji is drawn inside the previous one
+			i++
+			continue
+		}
+		w := runewidth.RuneWidth(r)
+		if i+1 < len(runes) && runes[i+1] == variationEmoji {
+			w = 2
+		}
+		width += w
+	}
+	return width
+}
+
+// stripANSI removes escape sequences from s
+func stripANSI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if n := sequenceLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
+
+// sequenceLen returns the length of the CSI (ESC [ ... final) or OSC
+// (ESC ] ... BEL or ST) sequence at the start of s, or 0 if there is none
+func sequenceLen(s string) int {
+	if len(s) < 2 || s[0] != '\033' {
+		return 0
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+// truncateWidth cuts s to at most width cells, ending in "…" when anything
+// was removed. Escape sequences are kept, and colors are reset after the cut.
+func truncateWidth(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	used, escaped := 0, false
+	for i := 0; i < len(s); {
+		if n := sequenceLen(s[i:]); n > 0 {
+			b.WriteString(s[i : i+n])
+			i += n
+			escaped = true
+			continue
+		}
+		// Keep variation selectors and joined emoji with the character before them
+		_, size := utf8.DecodeRuneInString(s[i:])
+		for i+size < len(s) {
+			r, n := utf8.DecodeRuneInString(s[i+size:])
+			if r != variationText && r != variationEmoji && r != zeroWidthJoin {
+				break
+			}
+			size += n
+			if r == zeroWidthJoin && i+size < len(s) {
+				_, joined := utf8.DecodeRuneInString(s[i+size:])
+				size += joined
+			}
+		}
+		w := Width(s[i : i+size])
+		if used+w > width-1 {
+			break
+		}
+		b.WriteString(s[i : i+size])
+		used += w
+		i += size
+	}
+	b.WriteString("…")
+	if escaped {
+		b.WriteString(color.Reset)
+	}
+	return b.String()
+}
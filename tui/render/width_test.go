@@ -0,0 +1,93 @@
+package render
+
+import (
+	"testing"
+
+	"statusline-config/config"
+)
+
+func TestWidth(t *testing.T) {
+	tests := map[string]int{
+		"main":                         4,
+		"\033[38;5;208mmain\033[0m":    4,
+		"\033]0;title\a│ x":            3,
+		"🌕":                            2,
+		"⚠️":                           2, // Text symbol shown as emoji by VS16
+		"👩‍💻":                          2, // Joined emoji
+		"日本":                           4,
+		"\033[1m🔔 WAITING\033[0m (2m)": 15,
+	}
+	for s, want := range tests {
+		if got := Width(s); got != want {
+			t.Errorf("Width(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"statusline", 20, "statusline"},
+		{"statusline", 6, "statu…"},
+		{"statusline", 0, ""},
+		{"\033[31mstatusline\033[0m", 4, "\033[31msta…\033[0m"},
+		{"ab🌕cd", 3, "ab…"}, // The emoji doesn't fit in one cell
+		{"⚠️ warn", 3, "⚠️…"},
+	}
+	for _, tt := range tests {
+		got := truncateWidth(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("truncateWidth(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if Width(got) > tt.width {
+			t.Errorf("truncateWidth(%q, %d) is %d cells wide", tt.s, tt.width, Width(got))
+		}
+	}
+}
+
+// fixedSegment renders the same text every time
+type fixedSegment struct{ name, text string }
+
+func (s fixedSegment) Name() string                { return s.name }
+func (s fixedSegment) Enabled(*config.Config) bool { return true }
+func (s fixedSegment) Render(*Context) string      { return s.text }
+
+func TestRenderRowFitsWidth(t *testing.T) {
+	segs := []Registration{
+		{Segment: fixedSegment{"model", "Opus"}, Priority: 90},
+		{Segment: fixedSegment{"dir", "~/src/statusline"}, Priority: 50,
+			Short: func(*Context) string { return "statusline" }},
+		{Segment: fixedSegment{"cost", "$1.84"}, Priority: 20},
+		{Segment: fixedSegment{"time", "1h12m"}, Priority: 20},
+	}
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{0, "Opus | ~/src/statusline | $1.84 | 1h12m"},
+		{40, "Opus | ~/src/statusline | $1.84 | 1h12m"},
+		{38, "Opus | ~/src/statusline | $1.84"}, // The later of equals goes first
+		{30, "Opus | ~/src/statusline"},
+		{20, "Opus | statusline"}, // Shortened before it is dropped
+		{12, "Opus"},
+		{3, ""},
+	}
+	for _, tt := range tests {
+		ctx := testContext(t, `{}`)
+		ctx.MaxWidth = tt.width
+		if got := renderRow(ctx, segs, " | "); got != tt.want {
+			t.Errorf("width %d: %q, want %q", tt.width, got, tt.want)
+		}
+	}
+
+	// Priorities in the config override the registered ones
+	ctx := testContext(t, `{}`)
+	ctx.MaxWidth = 30
+	ctx.Config.Display.Priorities = map[string]int{"model": 10}
+	if got, want := renderRow(ctx, segs, " | "), "~/src/statusline | $1.84"; got != want {
+		t.Errorf("with model at priority 10: %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,270 @@
+// Package state stores per-session statusline state, such as whether Claude
+// is waiting for input. It is shared by the hook side, which writes the
+// state, and the render side, which reads it.
+//
+// Each session is kept in its own JSON file so concurrent sessions never
+// overwrite each other. Writers take a lock directory next to the file
+// (mkdir is atomic everywhere, including from shell scripts) and replace the
+// file with a rename so readers never see a partial write.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DirName is the directory under ~/.claude holding one file per session
+const DirName = ".statusline-state.d"
+
+// legacyFileName is the single global state file used before states were per-session
+const legacyFileName = ".statusline-state.json"
+
+// DefaultMaxAge is how long an untouched session file is kept before garbage collection
+const DefaultMaxAge = 24 * time.Hour
+
+// gcInterval is how often GC actually looks at the directory; gcMarker, in
+// the directory, is touched each time it does
+const (
+	gcInterval = time.Hour
+	gcMarker   = ".last-gc"
+)
+
+// Lock timing
+const (
+	lockWait  = 2 * time.Second
+	lockStale = 10 * time.Second
+	lockRetry = 10 * time.Millisecond
+)
+
+// ErrLocked is returned when the session lock could not be acquired in time
+var ErrLocked = errors.New("state: session is locked")
+
+// Entry is the state of a single session
+type Entry struct {
+	Waiting   bool   `json:"waiting"`
+	Type      string `json:"type,omitempty"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Message   string `json:"message,omitempty"`
+	// Alerts records threshold alerts that have fired, keyed "<kind>:<channel>",
+	// until usage drops below the threshold again
+	Alerts map[string]bool `json:"alerts,omitempty"`
+}
+
+// Store reads and writes session entries in a directory
+type Store struct {
+	Dir    string
+	MaxAge time.Duration
+}
+
+// DefaultStore returns the store in ~/.claude
+func DefaultStore() (*Store, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{
+		Dir:    filepath.Join(homeDir, ".claude", DirName),
+		MaxAge: DefaultMaxAge,
+	}, nil
+}
+
+// SessionKey turns a session id into a safe file name
+func SessionKey(sessionID string) string {
+	key := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return -1
+	}, sessionID)
+	key = strings.TrimLeft(key, ".")
+	if key == "" {
+		return "default"
+	}
+	return key
+}
+
+func (s *Store) path(sessionID string) string {
+	return filepath.Join(s.Dir, SessionKey(sessionID)+".json")
+}
+
+// Get returns the entry for a session, or an empty entry if there is none
+func (s *Store) Get(sessionID string) (*Entry, error) {
+	entry := &Entry{}
+	data, err := os.ReadFile(s.path(sessionID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entry, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, entry); err != nil {
+		// A corrupt file is treated as no state rather than breaking the statusline
+		return &Entry{}, nil
+	}
+	return entry, nil
+}
+
+// Update applies fn to the session entry while holding its lock and writes the result
+func (s *Store) Update(sessionID string, fn func(e *Entry) error) error {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+	path := s.path(sessionID)
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entry, err := s.Get(sessionID)
+	if err != nil {
+		return err
+	}
+	if err := fn(entry); err != nil {
+		return err
+	}
+	return writeAtomic(path, entry)
+}
+
+// Delete removes the session entry; without a state directory there is
+// nothing to remove
+func (s *Store) Delete(sessionID string) error {
+	if _, err := os.Stat(s.Dir); os.IsNotExist(err) {
+		return nil
+	}
+	path := s.path(sessionID)
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// GC removes session files that have not been written for longer than MaxAge,
+// and moves the legacy global state file into the default session. It does
+// nothing if it already ran within the last hour, so callers can run it on
+// every render.
+func (s *Store) GC(now time.Time) error {
+	marker := filepath.Join(s.Dir, gcMarker)
+	if info, err := os.Stat(marker); err == nil && now.Sub(info.ModTime()) < gcInterval {
+		return nil
+	}
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		return err
+	}
+	os.Chtimes(marker, now, now)
+
+	if err := s.migrateLegacy(); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return err
+	}
+	maxAge := s.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || now.Sub(info.ModTime()) <= maxAge {
+			continue
+		}
+		s.Delete(strings.TrimSuffix(e.Name(), ".json"))
+	}
+	return nil
+}
+
+// migrateLegacy moves a waiting state left in the legacy global file into
+// the default session, unless that session has state of its own, and then
+// removes the file
+func (s *Store) migrateLegacy() error {
+	legacy := filepath.Join(filepath.Dir(s.Dir), legacyFileName)
+	data, err := os.ReadFile(legacy)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var old Entry
+	if json.Unmarshal(data, &old) == nil && old.Waiting {
+		err := s.Update("", func(e *Entry) error {
+			if !e.Waiting && e.Timestamp == 0 {
+				e.Waiting, e.Type, e.Timestamp = old.Waiting, old.Type, old.Timestamp
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return os.Remove(legacy)
+}
+
+// lock takes the lock directory for path, breaking locks left behind by crashed writers
+func lock(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockWait)
+	for {
+		err := os.Mkdir(lockPath, 0755)
+		if err == nil {
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrLocked
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// writeAtomic writes v as JSON to a temp file and renames it over path
+func writeAtomic(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,179 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSessionKey(t *testing.T) {
+	tests := map[string]string{
+		"3f2a-41b0_x.y": "3f2a-41b0_x.y",
+		"../../etc/pwd": "etcpwd",
+		"a/b:c d":       "abcd",
+		"...":           "default",
+		"":              "default",
+	}
+	for id, want := range tests {
+		if got := SessionKey(id); got != want {
+			t.Errorf("SessionKey(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestSessionsAreSeparate(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	err := s.Update("one", func(e *Entry) error {
+		e.Waiting = true
+		e.Message = `needs "quotes"` + "\nand newlines"
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	one, err := s.Get("one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !one.Waiting || one.Message != `needs "quotes"`+"\nand newlines" {
+		t.Errorf("Get(one) = %+v, want the entry as written", one)
+	}
+	two, err := s.Get("two")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if two.Waiting {
+		t.Error("Get(two) is waiting, want a session of its own")
+	}
+
+	if err := s.Delete("one"); err != nil {
+		t.Fatal(err)
+	}
+	if one, _ := s.Get("one"); one.Waiting {
+		t.Error("Get(one) after Delete is still waiting")
+	}
+}
+
+func TestMissingDir(t *testing.T) {
+	s := &Store{Dir: filepath.Join(t.TempDir(), ".statusline-state.d")}
+	if err := s.Delete("one"); err != nil {
+		t.Fatalf("Delete without a state directory: %v", err)
+	}
+	if entry, err := s.Get("one"); err != nil || entry.Waiting {
+		t.Errorf("Get without a state directory = %+v, %v", entry, err)
+	}
+	if err := s.Update("one", func(e *Entry) error { e.Waiting = true; return nil }); err != nil {
+		t.Fatalf("Update without a state directory: %v", err)
+	}
+}
+
+func TestCorruptEntryReadsEmpty(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	if err := os.WriteFile(s.path("broken"), []byte(`{"waiting": tru`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := s.Get("broken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Waiting {
+		t.Error("a corrupt entry reads as waiting")
+	}
+}
+
+func TestConcurrentUpdates(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	const writers = 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := s.Update("shared", func(e *Entry) error {
+				e.Message += fmt.Sprint(i % 10)
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	entry, err := s.Get("shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entry.Message) != writers {
+		t.Errorf("%d of %d updates survived", len(entry.Message), writers)
+	}
+}
+
+func TestGC(t *testing.T) {
+	claude := t.TempDir()
+	s := &Store{Dir: filepath.Join(claude, DirName), MaxAge: time.Hour}
+	for _, id := range []string{"old", "new"} {
+		if err := s.Update(id, func(e *Entry) error { e.Waiting = true; return nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	if err := os.Chtimes(s.path("old"), now, now.Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.GC(now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.path("old")); !os.IsNotExist(err) {
+		t.Error("GC kept a session untouched for longer than MaxAge")
+	}
+	if _, err := os.Stat(s.path("new")); err != nil {
+		t.Errorf("GC removed a recent session: %v", err)
+	}
+
+	// Within the hour GC leaves everything alone
+	if err := os.Chtimes(s.path("new"), now, now.Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.GC(now.Add(30 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.path("new")); err != nil {
+		t.Errorf("GC ran again within the hour: %v", err)
+	}
+	if err := s.GC(now.Add(61 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.path("new")); !os.IsNotExist(err) {
+		t.Error("GC did not run again after an hour")
+	}
+}
+
+func TestGCMigratesLegacyState(t *testing.T) {
+	claude := t.TempDir()
+	s := &Store{Dir: filepath.Join(claude, DirName)}
+	legacy := filepath.Join(claude, legacyFileName)
+	if err := os.WriteFile(legacy, []byte(`{"waiting": true, "type": "permission", "timestamp": 1700000000}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.GC(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Error("GC kept the legacy global state file after migrating it")
+	}
+	entry, err := s.Get("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !entry.Waiting || entry.Type != "permission" || entry.Timestamp != 1700000000 {
+		t.Errorf("default session after migration = %+v, want the legacy waiting state", entry)
+	}
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -8,6 +9,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"statusline-config/config"
+	"statusline-config/render"
 	"statusline-config/ui/views"
 )
 
@@ -18,9 +20,11 @@ const (
 	ScreenMenu Screen = iota
 	ScreenSections
 	ScreenIcons
+	ScreenColors
 	ScreenMascot
 	ScreenDisplay
 	ScreenNotifications
+	ScreenBackups
 	ScreenConfirmQuit
 )
 
@@ -67,6 +71,8 @@ type Model struct {
 	Width       int
 	Height      int
 	Error       string
+	Notice      string         // Informational status, e.g. what was migrated on load
+	Project     *views.Project // Set in --project mode, where only the overlay is saved
 	ShowHelp    bool
 	ConfirmQuit bool
 
@@ -74,9 +80,11 @@ type Model struct {
 	MenuView          *views.MenuView
 	SectionsView      *views.SectionsView
 	IconsView         *views.IconsView
+	ColorsView        *views.ColorsView
 	MascotView        *views.MascotView
 	DisplayView       *views.DisplayView
 	NotificationsView *views.NotificationsView
+	BackupsView       *views.BackupsView
 	PreviewView       *views.PreviewView
 }
 
@@ -85,7 +93,19 @@ func NewModel(cfg *config.Config) Model {
 	// Deep copy for dirty tracking
 	origCfg := *cfg
 
+	// An upgraded file is only written back when saved
+	var notice string
+	if len(cfg.Migrations) > 0 {
+		steps := make([]string, len(cfg.Migrations))
+		for i, m := range cfg.Migrations {
+			steps[i] = m.String()
+		}
+		notice = fmt.Sprintf("Migrated config %s; saving keeps the original as %s", strings.Join(steps, ", "), cfg.Migrations[0].Backup)
+	}
+	path, _ := config.GetConfigPath()
+
 	return Model{
+		Notice:            notice,
 		Config:            cfg,
 		OrigConfig:        &origCfg,
 		Screen:            ScreenMenu,
@@ -95,13 +115,37 @@ func NewModel(cfg *config.Config) Model {
 		MenuView:          views.NewMenuView(),
 		SectionsView:      views.NewSectionsView(cfg),
 		IconsView:         views.NewIconsView(cfg),
+		ColorsView:        views.NewColorsView(cfg),
 		MascotView:        views.NewMascotView(cfg),
 		DisplayView:       views.NewDisplayView(cfg),
 		NotificationsView: views.NewNotificationsView(cfg),
+		BackupsView:       views.NewBackupsView(path),
 		PreviewView:       views.NewPreviewView(cfg),
 	}
 }
 
+// NewProjectModel creates a model editing the project overlay at path. cfg is
+// the global config with the overlay applied; saving writes only the edits.
+func NewProjectModel(cfg *config.Config, path string) Model {
+	m := NewModel(cfg)
+	// Migrations of the global file are saved from the global editor
+	m.Notice = ""
+
+	project := &views.Project{Path: path, Loaded: cfg.Clone(), Config: cfg}
+	project.Refresh()
+	m.Project = project
+	m.SectionsView.Project = project
+	m.IconsView.Project = project
+	m.ColorsView.Project = project
+	m.MascotView.Project = project
+	m.DisplayView.Project = project
+	m.NotificationsView.Project = project
+	// Overlays are not backed up: the project's repository keeps their history
+	m.BackupsView = nil
+	m.MenuView.Items[6].Description = "Not kept for project overlays; use the repository's history"
+	return m
+}
+
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
 	return textinput.Blink
@@ -126,7 +170,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			case "s", "S":
 				// Save, install, and quit
-				if err := config.SaveAndInstall(m.Config); err != nil {
+				if msg := m.invalid(); msg != "" {
+					m.ConfirmQuit = false
+					m.Error = msg
+					return m, nil
+				}
+				if err := m.saveAndInstall(); err != nil {
 					m.Error = err.Error()
 				} else {
 					m.Dirty = false
@@ -145,35 +194,67 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, tea.Quit
 		case "ctrl+s":
-			if err := config.Save(m.Config); err != nil {
+			if msg := m.invalid(); msg != "" {
+				m.Error = msg
+				return m, nil
+			}
+			if err := m.save(); err != nil {
 				m.Error = err.Error()
 			} else {
 				m.Dirty = false
 				m.Error = ""
+				m.Notice = ""
 			}
 			return m, nil
 		}
 
-		// Screen-specific handling
-		switch m.Screen {
-		case ScreenMenu:
-			return m.updateMenu(msg)
-		case ScreenSections:
-			return m.updateSections(msg)
-		case ScreenIcons:
-			return m.updateIcons(msg)
-		case ScreenMascot:
-			return m.updateMascot(msg)
-		case ScreenDisplay:
-			return m.updateDisplay(msg)
-		case ScreenNotifications:
-			return m.updateNotifications(msg)
+		updated, cmd := m.updateScreen(msg)
+		// The project marks follow the overlay as it is edited
+		if next, ok := updated.(Model); ok && next.Project != nil && next.Dirty {
+			next.Project.Refresh()
 		}
+		return updated, cmd
 	}
 
 	return m, nil
 }
 
+// updateScreen handles a key on the current screen
+func (m Model) updateScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.Screen {
+	case ScreenMenu:
+		return m.updateMenu(msg)
+	case ScreenSections:
+		return m.updateSections(msg)
+	case ScreenIcons:
+		return m.updateIcons(msg)
+	case ScreenColors:
+		return m.updateColors(msg)
+	case ScreenMascot:
+		return m.updateMascot(msg)
+	case ScreenDisplay:
+		return m.updateDisplay(msg)
+	case ScreenNotifications:
+		return m.updateNotifications(msg)
+	case ScreenBackups:
+		return m.updateBackups(msg)
+	}
+	return m, nil
+}
+
+// invalid returns why the config cannot be saved, or "" when it validates.
+// The offending fields are marked on their screens.
+func (m Model) invalid() string {
+	errs := render.Validate(m.Config)
+	switch len(errs) {
+	case 0:
+		return ""
+	case 1:
+		return "Cannot save: " + errs[0].Error()
+	}
+	return fmt.Sprintf("Cannot save, %d invalid values: %s", len(errs), errs[0].Error())
+}
+
 func (m Model) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "up", "k":
@@ -188,21 +269,38 @@ func (m Model) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case 1:
 			m.Screen = ScreenIcons
 		case 2:
-			m.Screen = ScreenMascot
+			m.Screen = ScreenColors
 		case 3:
-			m.Screen = ScreenDisplay
+			m.Screen = ScreenMascot
 		case 4:
+			m.Screen = ScreenDisplay
+		case 5:
 			m.Screen = ScreenNotifications
-		case 6: // Save & Apply (index 6 because of separator)
-			if err := config.SaveAndInstall(m.Config); err != nil {
+		case 6:
+			if m.BackupsView == nil {
+				m.Error = "Project overlays have no backups; their earlier versions are in the repository's history"
+				return m, nil
+			}
+			m.BackupsView.Refresh()
+			m.Screen = ScreenBackups
+		case 8: // Save & Apply (index 8 because of separator)
+			if msg := m.invalid(); msg != "" {
+				m.Error = msg
+				return m, nil
+			}
+			if err := m.saveAndInstall(); err != nil {
 				m.Error = "Save failed: " + err.Error()
 				return m, nil
 			}
 			m.Dirty = false
 			m.Error = ""
 			return m, tea.Quit
-		case 7: // Save Config Only
-			if err := config.Save(m.Config); err != nil {
+		case 9: // Save Config Only
+			if msg := m.invalid(); msg != "" {
+				m.Error = msg
+				return m, nil
+			}
+			if err := m.save(); err != nil {
 				m.Error = "Save failed: " + err.Error()
 				return m, nil
 			}
@@ -226,9 +324,21 @@ func (m Model) updateSections(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.SectionsView.Up()
 	case "down", "j":
 		m.SectionsView.Down()
+	case "shift+up", "K":
+		m.SectionsView.MoveUp()
+		m.Dirty = true
+	case "shift+down", "J":
+		m.SectionsView.MoveDown()
+		m.Dirty = true
 	case " ", "x", "enter":
 		m.SectionsView.Toggle()
 		m.Dirty = true
+	case "+", "=":
+		m.SectionsView.AdjustPriority(5)
+		m.Dirty = true
+	case "-", "_":
+		m.SectionsView.AdjustPriority(-5)
+		m.Dirty = true
 	case "esc":
 		m.Screen = ScreenMenu
 	case "q":
@@ -263,6 +373,48 @@ func (m Model) updateIcons(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.IconsView.Down()
 	case "enter", "e":
 		m.IconsView.StartEdit()
+	case "p":
+		m.IconsView.CyclePreset()
+		m.Dirty = true
+	case "esc", "q":
+		m.Screen = ScreenMenu
+	}
+	return m, nil
+}
+
+func (m Model) updateColors(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.ColorsView.Editing {
+		switch msg.String() {
+		case "enter":
+			if m.ColorsView.StopEdit() {
+				m.Dirty = true
+			}
+			return m, nil
+		case "esc":
+			m.ColorsView.CancelEdit()
+			return m, nil
+		default:
+			// Forward to text input
+			var cmd tea.Cmd
+			input := m.ColorsView.CurrentInput()
+			*input, cmd = input.Update(msg)
+			return m, cmd
+		}
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		m.ColorsView.Up()
+	case "down", "j":
+		m.ColorsView.Down()
+	case "left", "h":
+		m.ColorsView.Cycle(-1)
+		m.Dirty = true
+	case "right", "l":
+		m.ColorsView.Cycle(1)
+		m.Dirty = true
+	case "enter", "e":
+		m.ColorsView.StartEdit()
 	case "esc", "q":
 		m.Screen = ScreenMenu
 	}
@@ -325,11 +477,32 @@ func (m Model) updateMascot(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) updateDisplay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.DisplayView.EditingMultiline() {
+		switch msg.String() {
+		case "ctrl+d":
+			if m.DisplayView.StopEdit() {
+				m.Dirty = true
+			}
+			return m, nil
+		case "esc":
+			m.DisplayView.CancelEdit()
+			return m, nil
+		default:
+			// Forward to the text area and follow along in the preview
+			var cmd tea.Cmd
+			area := m.DisplayView.CurrentArea()
+			*area, cmd = area.Update(msg)
+			m.DisplayView.UpdateDraft()
+			return m, cmd
+		}
+	}
+
 	if m.DisplayView.Editing {
 		switch msg.String() {
 		case "enter":
-			m.DisplayView.StopEdit()
-			m.Dirty = true
+			if m.DisplayView.StopEdit() {
+				m.Dirty = true
+			}
 			return m, nil
 		case "esc":
 			m.DisplayView.CancelEdit()
@@ -348,17 +521,123 @@ func (m Model) updateDisplay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.DisplayView.Up()
 	case "down", "j":
 		m.DisplayView.Down()
+	case "left", "h":
+		if m.DisplayView.HasOptions() {
+			m.DisplayView.Cycle(-1)
+			m.Dirty = true
+		}
+	case "right", "l":
+		if m.DisplayView.HasOptions() {
+			m.DisplayView.Cycle(1)
+			m.Dirty = true
+		}
 	case "enter", "e":
-		m.DisplayView.StartEdit()
+		if m.DisplayView.HasOptions() {
+			m.DisplayView.Cycle(1)
+			m.Dirty = true
+		} else {
+			m.DisplayView.StartEdit()
+		}
 	case "esc", "q":
 		m.Screen = ScreenMenu
 	}
 	return m, nil
 }
 
+func (m Model) updateBackups(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.BackupsView.Confirm {
+		switch msg.String() {
+		case "y", "Y":
+			return m.restoreBackup()
+		case "n", "N", "esc":
+			m.BackupsView.Confirm = false
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		m.BackupsView.Up()
+	case "down", "j":
+		m.BackupsView.Down()
+	case "enter":
+		if _, ok := m.BackupsView.Current(); ok {
+			m.BackupsView.Confirm = true
+		}
+	case "esc", "q":
+		m.Screen = ScreenMenu
+	}
+	return m, nil
+}
+
+// restoreBackup puts the selected backup in place and reloads the editor
+// from it, dropping unsaved changes
+func (m Model) restoreBackup() (tea.Model, tea.Cmd) {
+	backup, _ := m.BackupsView.Current()
+	m.BackupsView.Confirm = false
+	if err := config.RestoreBackup(m.BackupsView.Path, backup); err != nil {
+		m.Error = "Restore failed: " + err.Error()
+		return m, nil
+	}
+	restored, err := m.reload()
+	if err != nil {
+		m.Error = "Restore failed: " + err.Error()
+		return m, nil
+	}
+	restored.Width, restored.Height = m.Width, m.Height
+	restored.Screen = ScreenBackups
+	notice := "Restored the config saved " + backup.Saved.Format("2006-01-02 15:04:05")
+	if restored.Notice != "" {
+		notice += "; " + restored.Notice
+	}
+	restored.Notice = notice
+	return restored, nil
+}
+
+// reload returns a fresh editor for the config as it is on disk
+func (m Model) reload() (Model, error) {
+	path, err := config.GetConfigPath()
+	if err != nil {
+		return m, err
+	}
+	if m.Project == nil {
+		cfg, err := config.LoadFromPath(path)
+		if err != nil {
+			return m, err
+		}
+		return NewModel(cfg), nil
+	}
+	cfg, err := config.LoadWithOverlay(path, m.Project.Path)
+	if err != nil {
+		return m, err
+	}
+	return NewProjectModel(cfg, m.Project.Path), nil
+}
+
+// save writes the config, or in --project mode what the overlay changes
+func (m Model) save() error {
+	if m.Project != nil {
+		if err := config.SaveOverlay(m.Project.Loaded, m.Config, m.Project.Path); err != nil {
+			return err
+		}
+		m.Project.Loaded = m.Config.Clone()
+		m.Project.Refresh()
+		return nil
+	}
+	return config.Save(m.Config)
+}
+
+// saveAndInstall saves and points Claude Code at the renderer
+func (m Model) saveAndInstall() error {
+	if err := m.save(); err != nil {
+		return err
+	}
+	return config.InstallStatusline()
+}
+
 func (m Model) updateNotifications(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Handle text input editing
-	if m.NotificationsView.EditingThreshold || m.NotificationsView.EditingTitle {
+	if m.NotificationsView.EditingThreshold || m.NotificationsView.EditingTitle || m.NotificationsView.EditingText {
 		switch msg.String() {
 		case "enter":
 			m.NotificationsView.Enter()
@@ -530,6 +809,10 @@ func (m Model) View() string {
 		Align(lipgloss.Center)
 	content.WriteString(subtitleStyle.Render("Claude Statusline Configuration Tool  v1.0"))
 	content.WriteString("\n")
+	if m.Project != nil {
+		content.WriteString(subtitleStyle.Render("Project " + m.Project.Path + ": ● values are set here, the rest inherited from the global config"))
+		content.WriteString("\n")
+	}
 
 	// Status bar (dirty indicator / error)
 	statusStyle := lipgloss.NewStyle().
@@ -542,6 +825,10 @@ func (m Model) View() string {
 			Foreground(lipgloss.Color("#EF4444")).
 			Bold(true)
 		content.WriteString(statusStyle.Render(errorStyle.Render("Error: " + m.Error)))
+	} else if m.Notice != "" {
+		noticeStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#0EA5E9"))
+		content.WriteString(statusStyle.Render(noticeStyle.Render(m.Notice)))
 	} else if m.Dirty {
 		dirtyStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#F59E0B")).
@@ -564,7 +851,13 @@ func (m Model) View() string {
 	} else {
 		headerHeight = 13 // Small ASCII (3 lines) + sparkle borders (2) + subtitle + status + extra top padding
 	}
-	footerHeight := 6 // Preview + help
+	if m.Project != nil {
+		headerHeight++ // Project line
+	}
+	footerHeight := lipgloss.Height(m.PreviewView.Render()) + 4 // Preview rows + help
+	if m.Screen == ScreenDisplay {
+		footerHeight += len(config.GaugeStyles()) + 2 // Gauge comparison
+	}
 	contentHeight := m.Height - headerHeight - footerHeight
 	if contentHeight < 10 {
 		contentHeight = 10
@@ -618,12 +911,16 @@ func (m Model) View() string {
 			screenContent = m.SectionsView.Render()
 		case ScreenIcons:
 			screenContent = m.IconsView.Render()
+		case ScreenColors:
+			screenContent = m.ColorsView.Render()
 		case ScreenMascot:
 			screenContent = m.MascotView.Render()
 		case ScreenDisplay:
 			screenContent = m.DisplayView.Render()
 		case ScreenNotifications:
 			screenContent = m.NotificationsView.Render()
+		case ScreenBackups:
+			screenContent = m.BackupsView.Render()
 		}
 
 		contentBox := contentBoxStyle.Render(screenContent)
@@ -641,7 +938,11 @@ func (m Model) View() string {
 		previewStyle := lipgloss.NewStyle().
 			Width(m.Width - 4).
 			Padding(0, 2)
-		preview := previewStyle.Render(m.PreviewView.Render())
+		previewContent := m.PreviewView.Render()
+		if m.Screen == ScreenDisplay {
+			previewContent += "\n" + m.PreviewView.RenderGauges()
+		}
+		preview := previewStyle.Render(previewContent)
 		content.WriteString(preview)
 		content.WriteString("\n")
 	}
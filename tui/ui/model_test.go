@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"statusline-config/config"
+)
+
+// testHome points the home directory at a temp dir and returns the global config path
+func testHome(t *testing.T) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	path, err := config.GetConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// keyMsg returns the key press bubbletea sends for a key name
+func keyMsg(s string) tea.KeyMsg {
+	switch s {
+	case "ctrl+s":
+		return tea.KeyMsg{Type: tea.KeyCtrlS}
+	case "ctrl+c":
+		return tea.KeyMsg{Type: tea.KeyCtrlC}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// press sends each key to the model in turn
+func press(m Model, keyNames ...string) Model {
+	for _, name := range keyNames {
+		next, _ := m.Update(keyMsg(name))
+		m = next.(Model)
+	}
+	return m
+}
+
+func TestMigratedConfigStartsClean(t *testing.T) {
+	path := testHome(t)
+	orig := []byte(`{"version": "1.0", "icons": {"moons": ["🌑", "🌕"]}, "thresholds": {"moon_phases": [50]}}`)
+	if err := os.WriteFile(path, orig, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(cfg)
+	if m.Dirty {
+		t.Error("a migrated config starts the editor dirty")
+	}
+	backup := config.MigrationBackup(path, "1.0")
+	if !strings.Contains(m.Notice, backup) {
+		t.Errorf("Notice = %q, want it to name the backup %s", m.Notice, backup)
+	}
+	if _, err := os.Stat(backup); !os.IsNotExist(err) {
+		t.Error("opening the editor wrote the migration backup")
+	}
+
+	m = press(m, "ctrl+s")
+	if m.Error != "" || m.Notice != "" {
+		t.Errorf("after saving: error %q, notice %q", m.Error, m.Notice)
+	}
+	if data, err := os.ReadFile(backup); err != nil || string(data) != string(orig) {
+		t.Errorf("saving kept %q, %v; want the original file", data, err)
+	}
+}
+
+func TestSaveRefusesInvalidConfig(t *testing.T) {
+	path := testHome(t)
+	m := NewModel(config.DefaultConfig())
+	m.Config.Display.Style = "fancy"
+
+	m = press(m, "ctrl+s")
+	if !strings.HasPrefix(m.Error, "Cannot save: display.style") {
+		t.Errorf("Error = %q, want the invalid display.style", m.Error)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("an invalid config was saved")
+	}
+}
+
+func TestQuitAsksWhenDirty(t *testing.T) {
+	testHome(t)
+	m := NewModel(config.DefaultConfig())
+	m.Screen = ScreenSections
+	m = press(m, "x")
+	if !m.Dirty {
+		t.Fatal("toggling a section left the editor clean")
+	}
+
+	m = press(m, "ctrl+c")
+	if !m.ConfirmQuit {
+		t.Fatal("quitting with unsaved changes did not ask first")
+	}
+	m = press(m, "n")
+	if m.ConfirmQuit || !m.Dirty {
+		t.Errorf("declining to quit: ConfirmQuit %v, Dirty %v", m.ConfirmQuit, m.Dirty)
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	path := testHome(t)
+	cfg := config.DefaultConfig()
+	cfg.Display.Separator = " old "
+	if err := config.SaveToPath(cfg, path); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Display.Separator = " new "
+	if err := config.SaveToPath(cfg, path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := config.LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(loaded)
+	m.Screen = ScreenBackups
+	m.BackupsView.Refresh()
+	m = press(m, "enter", "y")
+
+	if m.Error != "" {
+		t.Fatalf("restore failed: %s", m.Error)
+	}
+	if m.Config.Display.Separator != " old " {
+		t.Errorf("after restoring, separator %q, want %q", m.Config.Display.Separator, " old ")
+	}
+	if !strings.HasPrefix(m.Notice, "Restored the config saved ") || m.Screen != ScreenBackups {
+		t.Errorf("notice %q on screen %d", m.Notice, m.Screen)
+	}
+}
+
+func TestProjectSaveWritesOverlay(t *testing.T) {
+	global := testHome(t)
+	overlay := filepath.Join(t.TempDir(), ".lunar.json")
+	if err := os.WriteFile(overlay, []byte(`{"display": {"max_width": 90}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadWithOverlay(global, overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewProjectModel(cfg, overlay)
+	m.Config.Display.Separator = " :: "
+	m = press(m, "ctrl+s")
+	if m.Error != "" {
+		t.Fatal(m.Error)
+	}
+
+	if _, err := os.Stat(global); !os.IsNotExist(err) {
+		t.Error("saving a project config wrote the global config")
+	}
+	data, err := os.ReadFile(overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"separator": " :: "`, `"max_width": 90`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("overlay =\n%s\nwant it to set %s", data, want)
+		}
+	}
+}
+
+func TestProjectHasNoBackups(t *testing.T) {
+	global := testHome(t)
+	overlay := filepath.Join(t.TempDir(), ".lunar.json")
+	cfg, err := config.LoadWithOverlay(global, overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewProjectModel(cfg, overlay)
+	m.MenuView.Selected = 6
+	m = press(m, "enter")
+	if m.Screen == ScreenBackups || m.Error == "" {
+		t.Errorf("choosing Backups for an overlay opened screen %d with error %q, want it refused", m.Screen, m.Error)
+	}
+	if _, err := os.Stat(overlay + ".d"); !os.IsNotExist(err) {
+		t.Error("the project overlay got a backups directory")
+	}
+}
+
+func TestProjectMarksFollowEdits(t *testing.T) {
+	global := testHome(t)
+	overlay := filepath.Join(t.TempDir(), ".lunar.json")
+	if err := os.WriteFile(overlay, []byte(`{"display": {"max_width": 90}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadWithOverlay(global, overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewProjectModel(cfg, overlay)
+	if got := m.Project.Overrides(); len(got) != 1 || !got["display.max_width"] {
+		t.Fatalf("Overrides on load = %v, want display.max_width", got)
+	}
+
+	m.Screen = ScreenSections
+	m = press(m, "x")
+	var toggled bool
+	for path := range m.Project.Overrides() {
+		toggled = toggled || strings.HasPrefix(path, "enabled_sections.")
+	}
+	if !toggled || !m.Project.Overrides()["display.max_width"] {
+		t.Errorf("Overrides after toggling a section = %v, want the section and display.max_width", m.Project.Overrides())
+	}
+}
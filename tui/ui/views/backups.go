@@ -0,0 +1,271 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"statusline-config/config"
+)
+
+// maxDiffLines is how many lines of the selected backup's diff are shown
+const maxDiffLines = 12
+
+// BackupsView lists earlier saved versions of the config file and restores them
+type BackupsView struct {
+	Path     string // The config file the backups belong to
+	Backups  []config.Backup
+	Selected int
+	Confirm  bool // Asking whether to restore the selected backup
+	Error    string
+}
+
+// NewBackupsView creates a backups view for the config file at path
+func NewBackupsView(path string) *BackupsView {
+	v := &BackupsView{Path: path}
+	v.Refresh()
+	return v
+}
+
+// Refresh reloads the list of backups, e.g. after a save added one
+func (v *BackupsView) Refresh() {
+	backups, err := config.ListBackups(v.Path)
+	v.Backups = backups
+	v.Error = ""
+	if err != nil {
+		v.Error = err.Error()
+	}
+	if v.Selected >= len(v.Backups) {
+		v.Selected = 0
+	}
+	v.Confirm = false
+}
+
+// Up moves selection up
+func (v *BackupsView) Up() {
+	if v.Confirm || len(v.Backups) == 0 {
+		return
+	}
+	v.Selected--
+	if v.Selected < 0 {
+		v.Selected = len(v.Backups) - 1
+	}
+}
+
+// Down moves selection down
+func (v *BackupsView) Down() {
+	if v.Confirm || len(v.Backups) == 0 {
+		return
+	}
+	v.Selected++
+	if v.Selected >= len(v.Backups) {
+		v.Selected = 0
+	}
+}
+
+// Current returns the selected backup, or false if there are none
+func (v *BackupsView) Current() (config.Backup, bool) {
+	if len(v.Backups) == 0 {
+		return config.Backup{}, false
+	}
+	return v.Backups[v.Selected], true
+}
+
+// diff compares the config file with a backup: "-" lines would go and "+"
+// lines come back if it were restored
+func (v *BackupsView) diff(backup config.Backup) ([]diffLine, error) {
+	current, err := os.ReadFile(v.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	old, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return nil, err
+	}
+	return diffLines(splitLines(string(current)), splitLines(string(old))), nil
+}
+
+// Render returns the backups view string
+func (v *BackupsView) Render() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7C3AED")).
+		MarginBottom(1)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#10B981")).
+		Bold(true)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#9CA3AF"))
+
+	descStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+
+	addStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#10B981"))
+
+	removeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#EF4444"))
+
+	warnStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#F59E0B")).
+		Bold(true)
+
+	b.WriteString(titleStyle.Render("Backups"))
+	b.WriteString("\n\n")
+
+	if v.Error != "" {
+		b.WriteString(removeStyle.Render("  "+v.Error) + "\n\n")
+	}
+	if len(v.Backups) == 0 {
+		b.WriteString(descStyle.Render("  No backups yet. Each save keeps the version it replaces, up to " +
+			fmt.Sprint(config.MaxBackups) + ", in " + config.BackupDir(v.Path)))
+		b.WriteString("\n\n")
+		b.WriteString(descStyle.Render("  [esc] Back"))
+		return b.String()
+	}
+
+	start, end := visibleRange(v.Selected, len(v.Backups))
+	if start > 0 {
+		b.WriteString(descStyle.Render("  ↑ more") + "\n")
+	}
+	for i := start; i < end; i++ {
+		backup := v.Backups[i]
+		label := backup.Saved.Format("2006-01-02 15:04:05")
+		var summary string
+		if lines, err := v.diff(backup); err == nil {
+			added, removed := diffStat(lines)
+			summary = addStyle.Render(fmt.Sprintf("+%d", added)) + " " + removeStyle.Render(fmt.Sprintf("-%d", removed))
+		}
+		if i == v.Selected {
+			b.WriteString("  " + selectedStyle.Render("> "+label) + "  " + summary + "\n")
+		} else {
+			b.WriteString("    " + normalStyle.Render(label) + "  " + summary + "\n")
+		}
+	}
+	if end < len(v.Backups) {
+		b.WriteString(descStyle.Render("  ↓ more") + "\n")
+	}
+
+	// Diff of the selected backup against the file on disk
+	b.WriteString("\n")
+	backup, _ := v.Current()
+	lines, err := v.diff(backup)
+	switch {
+	case err != nil:
+		b.WriteString(removeStyle.Render("  "+err.Error()) + "\n")
+	case len(lines) == 0:
+		b.WriteString(descStyle.Render("  Same as the current file") + "\n")
+	default:
+		b.WriteString(descStyle.Render("  Restoring changes the current file:") + "\n")
+		shown := 0
+		for _, line := range lines {
+			if line.op == ' ' {
+				continue
+			}
+			if shown == maxDiffLines {
+				b.WriteString(descStyle.Render("    …") + "\n")
+				break
+			}
+			text := "    " + string(line.op) + " " + line.text
+			if line.op == '+' {
+				b.WriteString(addStyle.Render(text) + "\n")
+			} else {
+				b.WriteString(removeStyle.Render(text) + "\n")
+			}
+			shown++
+		}
+	}
+
+	b.WriteString("\n")
+	if v.Confirm {
+		b.WriteString(warnStyle.Render("  Restore this backup? Unsaved changes are lost.  [y] Restore  [n] Cancel"))
+	} else {
+		b.WriteString(descStyle.Render("  [enter] Restore  [esc] Back"))
+	}
+
+	return b.String()
+}
+
+// diffLine is one line of a diff: ' ' kept, '-' removed or '+' added
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// diffLines returns the line diff turning a into b, from their longest
+// common subsequence
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, diffLine{'-', a[i]})
+			i++
+		default:
+			out = append(out, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, diffLine{'+', b[j]})
+	}
+
+	for _, line := range out {
+		if line.op != ' ' {
+			return out
+		}
+	}
+	return nil
+}
+
+// diffStat counts the added and removed lines of a diff
+func diffStat(lines []diffLine) (added, removed int) {
+	for _, line := range lines {
+		switch line.op {
+		case '+':
+			added++
+		case '-':
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// splitLines splits text into lines, ignoring a final newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,46 @@
+package views
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	current := splitLines("{\n  \"separator\": \" | \",\n  \"style\": \"plain\",\n  \"max_width\": 80\n}\n")
+	backup := splitLines("{\n  \"separator\": \" • \",\n  \"style\": \"plain\"\n}\n")
+
+	got := diffLines(current, backup)
+	want := []diffLine{
+		{' ', "{"},
+		{'-', `  "separator": " | ",`},
+		{'-', `  "style": "plain",`},
+		{'-', `  "max_width": 80`},
+		{'+', `  "separator": " • ",`},
+		{'+', `  "style": "plain"`},
+		{' ', "}"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffLines =\n%q\nwant\n%q", got, want)
+	}
+	if added, removed := diffStat(got); added != 2 || removed != 3 {
+		t.Errorf("diffStat = +%d -%d, want +2 -3", added, removed)
+	}
+}
+
+func TestDiffLinesKeepsCommonLines(t *testing.T) {
+	a := []string{"a", "b", "c", "d"}
+	b := []string{"a", "c", "d", "e"}
+	want := []diffLine{{' ', "a"}, {'-', "b"}, {' ', "c"}, {' ', "d"}, {'+', "e"}}
+	if got := diffLines(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffLines = %q, want %q", got, want)
+	}
+}
+
+func TestDiffLinesSame(t *testing.T) {
+	if got := diffLines([]string{"a", "b"}, []string{"a", "b"}); got != nil {
+		t.Errorf("identical files diff to %q, want nil", got)
+	}
+	if got := diffLines(nil, splitLines("{}\n")); !reflect.DeepEqual(got, []diffLine{{'+', "{}"}}) {
+		t.Errorf("restoring over a missing file diffs to %q", got)
+	}
+}
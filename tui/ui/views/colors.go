@@ -0,0 +1,231 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+	"statusline-config/color"
+	"statusline-config/config"
+)
+
+// ColorItem represents an editable color
+type ColorItem struct {
+	Key         string
+	Label       string
+	Description string
+	Value       *string
+}
+
+// ColorsView handles the colors editing screen
+type ColorsView struct {
+	Items    []ColorItem
+	Selected int
+	Editing  bool
+	Input    textinput.Model
+	Error    string
+	Config   *config.Config
+	Project  *Project // Set when editing a project overlay
+}
+
+// NewColorsView creates a new colors view
+func NewColorsView(cfg *config.Config) *ColorsView {
+	ti := textinput.New()
+	ti.Placeholder = "bright_blue, 208 or #RRGGBB"
+	ti.CharLimit = 20
+	ti.Width = 20
+
+	return &ColorsView{
+		Items: []ColorItem{
+			{Key: "directory", Label: "Directory", Description: "Color of the directory section", Value: &cfg.Colors.Directory},
+			{Key: "git_clean", Label: "Git Clean", Description: "Color of the branch when the tree is clean", Value: &cfg.Colors.GitClean},
+			{Key: "git_dirty", Label: "Git Dirty", Description: "Color of the branch when there are uncommitted changes", Value: &cfg.Colors.GitDirty},
+			{Key: "model", Label: "Model", Description: "Color of the model name", Value: &cfg.Colors.Model},
+			{Key: "text", Label: "Text", Description: "Color of token count and percentage", Value: &cfg.Colors.Text},
+			{Key: "budget_ok", Label: "Budget OK", Description: "Color of the budget while under the warning level", Value: &cfg.Colors.BudgetOK},
+			{Key: "budget_warn", Label: "Budget Warning", Description: "Color of the budget from the warning level", Value: &cfg.Colors.BudgetWarn},
+			{Key: "budget_over", Label: "Budget Over", Description: "Color of the budget once it is used up", Value: &cfg.Colors.BudgetOver},
+			{Key: "waiting_bg", Label: "Waiting Background", Description: "Powerline background of the waiting indicator", Value: &cfg.Colors.WaitingBg},
+			{Key: "git_bg", Label: "Git Background", Description: "Powerline background of the git sections", Value: &cfg.Colors.GitBg},
+			{Key: "directory_bg", Label: "Directory Background", Description: "Powerline background of the directory", Value: &cfg.Colors.DirectoryBg},
+			{Key: "model_bg", Label: "Model Background", Description: "Powerline background of the model name", Value: &cfg.Colors.ModelBg},
+			{Key: "context_bg", Label: "Context Background", Description: "Powerline background of the gauge, token count and percentage", Value: &cfg.Colors.ContextBg},
+			{Key: "session_bg", Label: "Session Background", Description: "Powerline background of budget, cost, duration, API time and lines", Value: &cfg.Colors.SessionBg},
+			{Key: "mascot_bg", Label: "Mascot Background", Description: "Powerline background of the mascot", Value: &cfg.Colors.MascotBg},
+		},
+		Selected: 0,
+		Input:    ti,
+		Config:   cfg,
+	}
+}
+
+// Up moves selection up
+func (v *ColorsView) Up() {
+	if !v.Editing {
+		v.Selected--
+		if v.Selected < 0 {
+			v.Selected = len(v.Items) - 1
+		}
+	}
+}
+
+// Down moves selection down
+func (v *ColorsView) Down() {
+	if !v.Editing {
+		v.Selected++
+		if v.Selected >= len(v.Items) {
+			v.Selected = 0
+		}
+	}
+}
+
+// Cycle steps the selected color through the named palette
+func (v *ColorsView) Cycle(step int) {
+	if v.Editing {
+		return
+	}
+	names := color.Names()
+	value := v.Items[v.Selected].Value
+	idx := 0
+	for i, name := range names {
+		if name == *value {
+			idx = i
+			break
+		}
+	}
+	idx = (idx + step + len(names)) % len(names)
+	*value = names[idx]
+	v.Error = ""
+}
+
+// StartEdit begins editing the selected item
+func (v *ColorsView) StartEdit() {
+	v.Input.SetValue(*v.Items[v.Selected].Value)
+	v.Input.Focus()
+	v.Editing = true
+	v.Error = ""
+}
+
+// StopEdit validates and saves the edited color; it returns false if the value was rejected
+func (v *ColorsView) StopEdit() bool {
+	value := strings.TrimSpace(v.Input.Value())
+	if _, err := color.Parse(value); err != nil {
+		v.Error = err.Error()
+		return false
+	}
+	*v.Items[v.Selected].Value = value
+	v.Input.Blur()
+	v.Editing = false
+	v.Error = ""
+	return true
+}
+
+// CancelEdit cancels editing
+func (v *ColorsView) CancelEdit() {
+	v.Input.Blur()
+	v.Editing = false
+	v.Error = ""
+}
+
+// CurrentInput returns the input model
+func (v *ColorsView) CurrentInput() *textinput.Model {
+	return &v.Input
+}
+
+// swatch renders a block of the color followed by sample text in it
+func swatch(value string) string {
+	c, err := color.Parse(value)
+	if err != nil {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444")).Render("invalid")
+	}
+	if c.IsDefault() {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render("terminal default")
+	}
+	block := lipgloss.NewStyle().Background(c.Lipgloss()).Render("    ")
+	sample := lipgloss.NewStyle().Foreground(c.Lipgloss()).Render("Sample")
+	return block + " " + sample
+}
+
+// Render returns the colors view string
+func (v *ColorsView) Render() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7C3AED")).
+		MarginBottom(1)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#10B981")).
+		Bold(true)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#9CA3AF"))
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#F59E0B"))
+
+	descStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+
+	editingStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#3B82F6")).
+		Bold(true)
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#EF4444"))
+
+	b.WriteString(titleStyle.Render("Colors"))
+	b.WriteString("\n\n")
+
+	errs := v.Config.Validate()
+	overrides := v.Project.Overrides()
+	start, end := visibleRange(v.Selected, len(v.Items))
+	if start > 0 {
+		b.WriteString(descStyle.Render("  ↑ more") + "\n")
+	}
+	for i := start; i < end; i++ {
+		item := v.Items[i]
+		var label string
+		if i == v.Selected {
+			label = selectedStyle.Render(item.Label)
+		} else {
+			label = normalStyle.Render(item.Label)
+		}
+
+		var value string
+		if v.Editing && i == v.Selected {
+			value = editingStyle.Render(v.Input.View())
+		} else {
+			value = valueStyle.Render(*item.Value) + "  " + swatch(*item.Value)
+		}
+		value += overrideMark(overrides, "colors."+item.Key)
+
+		b.WriteString("  " + label + ": " + value)
+		if i == v.Selected {
+			b.WriteString("\n")
+			b.WriteString(descStyle.Render("      " + item.Description))
+			if v.Error != "" {
+				b.WriteString("\n")
+				b.WriteString(errorStyle.Render("      " + v.Error))
+			}
+		}
+		if !(v.Editing && i == v.Selected) {
+			b.WriteString(fieldErrors(errs, "colors."+item.Key))
+		}
+		b.WriteString("\n")
+	}
+	if end < len(v.Items) {
+		b.WriteString(descStyle.Render("  ↓ more") + "\n")
+	}
+
+	b.WriteString("\n")
+	if v.Editing {
+		b.WriteString(descStyle.Render("  [enter] Save  [esc] Cancel"))
+	} else {
+		b.WriteString(descStyle.Render("  [enter/e] Edit  [←/→] Cycle named colors  [esc] Back"))
+	}
+
+	return b.String()
+}
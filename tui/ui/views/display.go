@@ -5,17 +5,22 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
 	"statusline-config/config"
+	"statusline-config/render"
 )
 
 // DisplayItem represents a display option
 type DisplayItem struct {
 	Key         string
+	Path        string // JSON path of the value, for validation errors
 	Label       string
 	Description string
-	IsString    bool // true for string values, false for int
+	IsString    bool     // true for string values, false for int
+	Options     []string // if set, the value is cycled through these instead of typed
+	Multiline   bool     // edited in a text area, with the preview following every keystroke
 }
 
 // DisplayView handles the display options screen
@@ -24,7 +29,13 @@ type DisplayView struct {
 	Selected int
 	Editing  bool
 	Input    textinput.Model
+	Area     textarea.Model
+	Error    string
 	Config   *config.Config
+	Project  *Project // Set when editing a project overlay
+
+	// before holds a multi-line value while it is edited live, for CancelEdit
+	before string
 }
 
 // NewDisplayView creates a new display view
@@ -33,16 +44,34 @@ func NewDisplayView(cfg *config.Config) *DisplayView {
 	ti.CharLimit = 20
 	ti.Width = 15
 
+	ta := textarea.New()
+	ta.CharLimit = 2000
+	ta.SetWidth(60)
+	ta.SetHeight(4)
+
 	return &DisplayView{
 		Items: []DisplayItem{
-			{Key: "separator", Label: "Separator", Description: "Text between sections", IsString: true},
-			{Key: "dir_max_len", Label: "Directory Max Length", Description: "Maximum directory name length", IsString: false},
-			{Key: "dir_truncate", Label: "Directory Truncate To", Description: "Length to truncate directory to", IsString: false},
-			{Key: "token_k_format", Label: "Token K Format", Description: "Threshold for showing as 'k' format", IsString: false},
+			{Key: "style", Path: "display.style", Label: "Style", Description: "plain, or powerline arrows on colored backgrounds (needs a Nerd Font)", Options: config.DisplayStyles()},
+			{Key: "separator", Path: "display.separator", Label: "Separator", Description: "Text between sections", IsString: true},
+			{Key: "format", Path: "display.format", Label: "Format", Description: "Go template for the whole line, e.g. {{.Git}} {{.Dir}} ⟶ {{.Model}}; empty uses the sections", IsString: true, Multiline: true},
+			{Key: "max_width", Path: "display.max_width", Label: "Max Width", Description: "Widest the line may get in columns, 0 to only fit $COLUMNS", IsString: false},
+			{Key: "dir_style", Path: "display.directory_style", Label: "Directory Style", Description: "basename, path from the project root, fish-style ~/w/p/api, or full path with a middle ellipsis", Options: config.DirectoryStyles()},
+			{Key: "dir_max_len", Path: "thresholds.directory_max_length", Label: "Directory Max Length", Description: "Maximum directory width in columns before it is shortened", IsString: false},
+			{Key: "dir_truncate", Path: "thresholds.directory_truncate_to", Label: "Directory Truncate To", Description: "Columns kept when shortening (the ellipsis style uses the max length)", IsString: false},
+			{Key: "token_k_format", Path: "thresholds.token_k_format", Label: "Token K Format", Description: "Threshold for showing as 'k' format", IsString: false},
+			{Key: "gauge_style", Path: "display.gauge.style", Label: "Gauge Style", Description: "How context usage is drawn", Options: config.GaugeStyles()},
+			{Key: "gauge_width", Path: "display.gauge.widths", Label: "Gauge Width", Description: "Number of cells for the selected gauge style", IsString: false},
+			{Key: "moon_phases", Path: "thresholds.moon_phases", Label: "Moon Thresholds", Description: "Comma-separated % where each moon phase starts, in increasing order", IsString: false},
+			{Key: "git_cache_ttl", Path: "git.cache_ttl", Label: "Git Cache TTL (s)", Description: "Seconds to reuse git status while the index and HEAD are unchanged", IsString: false},
+			{Key: "git_timeout", Path: "git.timeout_ms", Label: "Git Timeout (ms)", Description: "Deadline for git before the last known status is shown as stale", IsString: false},
+			{Key: "budget_usd", Path: "budget.session_usd", Label: "Session Budget ($)", Description: "Spending cap per session in USD, 0 to disable", IsString: false},
+			{Key: "budget_warn", Path: "budget.warn_at", Label: "Budget Warning At", Description: "% of the budget from which it is shown as a warning", IsString: false},
+			{Key: "cost_precision", Path: "display.cost_precision", Label: "Cost Precision", Description: "Decimal places shown for the session cost", IsString: false},
 		},
 		Selected: 0,
 		Editing:  false,
 		Input:    ti,
+		Area:     ta,
 		Config:   cfg,
 	}
 }
@@ -50,36 +79,167 @@ func NewDisplayView(cfg *config.Config) *DisplayView {
 // GetValue returns the current value for an item
 func (v *DisplayView) GetValue(item DisplayItem) string {
 	switch item.Key {
+	case "style":
+		return v.Config.Display.Style
 	case "separator":
 		return v.Config.Display.Separator
+	case "format":
+		return v.Config.Display.Format
+	case "max_width":
+		return strconv.Itoa(v.Config.Display.MaxWidth)
+	case "dir_style":
+		return v.Config.Display.DirectoryStyle
 	case "dir_max_len":
 		return strconv.Itoa(v.Config.Thresholds.DirectoryMaxLength)
 	case "dir_truncate":
 		return strconv.Itoa(v.Config.Thresholds.DirectoryTruncateTo)
 	case "token_k_format":
 		return strconv.Itoa(v.Config.Thresholds.TokenKFormat)
+	case "gauge_style":
+		return v.Config.Display.Gauge.Style
+	case "gauge_width":
+		gauge := v.Config.Display.Gauge
+		return strconv.Itoa(gauge.Width(gauge.Style))
+	case "moon_phases":
+		phases := make([]string, len(v.Config.Thresholds.MoonPhases))
+		for i, p := range v.Config.Thresholds.MoonPhases {
+			phases[i] = strconv.Itoa(p)
+		}
+		return strings.Join(phases, ", ")
+	case "git_cache_ttl":
+		return strconv.Itoa(v.Config.Git.CacheTTL)
+	case "git_timeout":
+		return strconv.Itoa(v.Config.Git.TimeoutMs)
+	case "budget_usd":
+		return strconv.FormatFloat(v.Config.Budget.SessionUSD, 'f', 2, 64)
+	case "cost_precision":
+		return strconv.Itoa(v.Config.Display.CostPrecision)
+	case "budget_warn":
+		return strconv.Itoa(v.Config.Budget.WarnAt)
 	}
 	return ""
 }
 
-// SetValue sets the value for an item
-func (v *DisplayView) SetValue(item DisplayItem, value string) {
+// SetValue sets the value for an item, returning an error if it was rejected
+func (v *DisplayView) SetValue(item DisplayItem, value string) error {
 	switch item.Key {
+	case "style":
+		v.Config.Display.Style = value
 	case "separator":
 		v.Config.Display.Separator = value
+	case "format":
+		if err := render.ValidateFormat(render.PreviewContext(v.Config), value); err != nil {
+			return err
+		}
+		v.Config.Display.Format = value
+	case "max_width":
+		val, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || val < 0 {
+			return fmt.Errorf("width must be a number of columns, 0 for no limit")
+		}
+		v.Config.Display.MaxWidth = val
+	case "dir_style":
+		v.Config.Display.DirectoryStyle = value
 	case "dir_max_len":
-		if val, err := strconv.Atoi(value); err == nil {
-			v.Config.Thresholds.DirectoryMaxLength = val
+		val, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || val < 1 {
+			return fmt.Errorf("length must be a number of columns, at least 1")
 		}
+		v.Config.Thresholds.DirectoryMaxLength = val
 	case "dir_truncate":
-		if val, err := strconv.Atoi(value); err == nil {
-			v.Config.Thresholds.DirectoryTruncateTo = val
+		val, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || val < 1 || val > v.Config.Thresholds.DirectoryMaxLength {
+			return fmt.Errorf("length must be a number of columns from 1 to the max length (%d)", v.Config.Thresholds.DirectoryMaxLength)
 		}
+		v.Config.Thresholds.DirectoryTruncateTo = val
 	case "token_k_format":
-		if val, err := strconv.Atoi(value); err == nil {
-			v.Config.Thresholds.TokenKFormat = val
+		val, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || val < 0 {
+			return fmt.Errorf("threshold must be a number of tokens, 0 or more")
+		}
+		v.Config.Thresholds.TokenKFormat = val
+	case "gauge_style":
+		v.Config.Display.Gauge.Style = value
+	case "gauge_width":
+		val, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || val < 1 || val > 40 {
+			return fmt.Errorf("width must be a number from 1 to 40")
+		}
+		gauge := &v.Config.Display.Gauge
+		if gauge.Widths == nil {
+			gauge.Widths = map[string]int{}
+		}
+		gauge.Widths[gauge.Style] = val
+	case "moon_phases":
+		phases, err := parseMoonPhases(value)
+		if err != nil {
+			return err
+		}
+		v.Config.Thresholds.MoonPhases = phases
+	case "git_cache_ttl":
+		val, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || val < 0 {
+			return fmt.Errorf("TTL must be a number of seconds, 0 to always run git")
+		}
+		v.Config.Git.CacheTTL = val
+	case "git_timeout":
+		val, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || val < 0 {
+			return fmt.Errorf("timeout must be a number of milliseconds, 0 to wait for git")
+		}
+		v.Config.Git.TimeoutMs = val
+	case "budget_usd":
+		val, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(value), "$"), 64)
+		if err != nil || val < 0 {
+			return fmt.Errorf("budget must be an amount in USD, 0 to disable")
+		}
+		v.Config.Budget.SessionUSD = val
+	case "cost_precision":
+		val, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || val < 0 || val > 6 {
+			return fmt.Errorf("precision must be a number from 0 to 6")
 		}
+		v.Config.Display.CostPrecision = val
+	case "budget_warn":
+		val, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || val < 0 || val > 100 {
+			return fmt.Errorf("warning must be a percentage from 0 to 100")
+		}
+		v.Config.Budget.WarnAt = val
+	}
+	return nil
+}
+
+// parseMoonPhases reads a comma-separated list of increasing percentages
+func parseMoonPhases(value string) ([]int, error) {
+	var phases []int
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		val, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", field)
+		}
+		phases = append(phases, val)
+	}
+	if len(phases) == 0 {
+		return nil, fmt.Errorf("enter at least one threshold")
+	}
+	if err := config.ValidateMoonPhases(phases); err != nil {
+		return nil, err
+	}
+	return phases, nil
+}
+
+// path returns the JSON path an item edits; the gauge width belongs to the
+// selected gauge style
+func (v *DisplayView) path(item DisplayItem) string {
+	if item.Key == "gauge_width" {
+		return item.Path + "." + v.Config.Display.Gauge.Style
 	}
+	return item.Path
 }
 
 // Up moves selection up
@@ -102,26 +262,84 @@ func (v *DisplayView) Down() {
 	}
 }
 
+// HasOptions reports whether the selected item is cycled rather than typed
+func (v *DisplayView) HasOptions() bool {
+	return len(v.Items[v.Selected].Options) > 0
+}
+
+// Cycle steps the selected item through its options
+func (v *DisplayView) Cycle(step int) {
+	item := v.Items[v.Selected]
+	if v.Editing || len(item.Options) == 0 {
+		return
+	}
+	current := v.GetValue(item)
+	idx := 0
+	for i, opt := range item.Options {
+		if opt == current {
+			idx = i
+			break
+		}
+	}
+	idx = (idx + step + len(item.Options)) % len(item.Options)
+	v.SetValue(item, item.Options[idx])
+}
+
 // StartEdit begins editing the selected item
 func (v *DisplayView) StartEdit() {
 	item := v.Items[v.Selected]
-	v.Input.SetValue(v.GetValue(item))
-	v.Input.Focus()
+	if item.Multiline {
+		v.before = v.GetValue(item)
+		v.Area.SetValue(v.before)
+		v.Area.Focus()
+	} else {
+		v.Input.SetValue(v.GetValue(item))
+		v.Input.Focus()
+	}
 	v.Editing = true
+	v.Error = ""
+}
+
+// EditingMultiline reports whether the text area is being edited
+func (v *DisplayView) EditingMultiline() bool {
+	return v.Editing && v.Items[v.Selected].Multiline
 }
 
-// StopEdit finishes editing and saves
-func (v *DisplayView) StopEdit() {
+// UpdateDraft shows the text area's contents in the preview while it is
+// edited; template errors appear there rather than here
+func (v *DisplayView) UpdateDraft() {
+	if v.EditingMultiline() && v.Items[v.Selected].Key == "format" {
+		v.Config.Display.Format = v.Area.Value()
+	}
+}
+
+// StopEdit finishes editing and saves; it returns false if the value was rejected
+func (v *DisplayView) StopEdit() bool {
 	item := v.Items[v.Selected]
-	v.SetValue(item, v.Input.Value())
+	value := v.Input.Value()
+	if item.Multiline {
+		value = v.Area.Value()
+	}
+	if err := v.SetValue(item, value); err != nil {
+		v.Error = err.Error()
+		return false
+	}
 	v.Input.Blur()
+	v.Area.Blur()
 	v.Editing = false
+	v.Error = ""
+	return true
 }
 
-// CancelEdit cancels editing
+// CancelEdit cancels editing, restoring a multi-line value edited live
 func (v *DisplayView) CancelEdit() {
+	if v.EditingMultiline() {
+		v.Config.Display.Format = v.before
+	}
 	v.Input.Blur()
+	v.Area.Blur()
 	v.Editing = false
+	v.Error = ""
 }
 
 // CurrentInput returns the input model
@@ -129,6 +347,11 @@ func (v *DisplayView) CurrentInput() *textinput.Model {
 	return &v.Input
 }
 
+// CurrentArea returns the text area model
+func (v *DisplayView) CurrentArea() *textarea.Model {
+	return &v.Area
+}
+
 // Render returns the display view string
 func (v *DisplayView) Render() string {
 	var b strings.Builder
@@ -156,9 +379,14 @@ func (v *DisplayView) Render() string {
 		Foreground(lipgloss.Color("#3B82F6")).
 		Bold(true)
 
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#EF4444"))
+
 	b.WriteString(titleStyle.Render("Display Options"))
 	b.WriteString("\n\n")
 
+	errs := render.Validate(v.Config)
+	overrides := v.Project.Overrides()
 	for i, item := range v.Items {
 		var label string
 		if i == v.Selected {
@@ -168,30 +396,43 @@ func (v *DisplayView) Render() string {
 		}
 
 		var value string
-		if v.Editing && i == v.Selected {
+		if v.Editing && i == v.Selected && item.Multiline {
+			value = "\n" + editingStyle.Render(v.Area.View())
+		} else if v.Editing && i == v.Selected {
 			value = editingStyle.Render(v.Input.View())
 		} else {
 			val := v.GetValue(item)
 			if item.IsString {
 				value = valueStyle.Render(fmt.Sprintf("%q", val))
+			} else if len(item.Options) > 0 {
+				value = valueStyle.Render("< " + val + " >")
 			} else {
 				value = valueStyle.Render(val)
 			}
 		}
 
-		b.WriteString("  " + label + ": " + value)
+		b.WriteString("  " + label + ": " + value + overrideMark(overrides, v.path(item)))
 		if i == v.Selected {
 			b.WriteString("\n")
 			b.WriteString(descStyle.Render("      " + item.Description))
+			if v.Error != "" {
+				b.WriteString("\n")
+				b.WriteString(errorStyle.Render("      " + v.Error))
+			}
+		}
+		if !(v.Editing && i == v.Selected) {
+			b.WriteString(fieldErrors(errs, v.path(item)))
 		}
 		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
-	if v.Editing {
+	if v.EditingMultiline() {
+		b.WriteString(descStyle.Render("  [ctrl+d] Save  [enter] New line  [esc] Cancel"))
+	} else if v.Editing {
 		b.WriteString(descStyle.Render("  [enter] Save  [esc] Cancel"))
 	} else {
-		b.WriteString(descStyle.Render("  [enter/e] Edit  [esc] Back"))
+		b.WriteString(descStyle.Render("  [enter/e] Edit  [←/→] Change option  [esc] Back"))
 	}
 
 	return b.String()
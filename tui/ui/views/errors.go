@@ -0,0 +1,26 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"statusline-config/config"
+)
+
+// fieldErrors renders the validation errors at path or below it, one per line
+// under the field, or "" when it is valid
+func fieldErrors(errs []config.FieldError, path string) string {
+	msgs := config.ErrorsAt(errs, path)
+	if len(msgs) == 0 {
+		return ""
+	}
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#EF4444"))
+
+	var b strings.Builder
+	for _, msg := range msgs {
+		b.WriteString("\n")
+		b.WriteString(errorStyle.Render("      ⚠ " + msg))
+	}
+	return b.String()
+}
@@ -1,6 +1,7 @@
 package views
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -13,6 +14,8 @@ type IconItem struct {
 	Key         string
 	Label       string
 	Description string
+	Value       *string // nil for moon phases, which live in Icons.Moons
+	Moon        int     // index into Icons.Moons for moon phases
 	Input       textinput.Model
 }
 
@@ -22,19 +25,36 @@ type IconsView struct {
 	Selected int
 	Editing  bool
 	Config   *config.Config
+	Preset   string   // Last preset applied with CyclePreset
+	Kept     int      // Custom icons CyclePreset left as they were
+	Project  *Project // Set when editing a project overlay
 }
 
 // NewIconsView creates a new icons view
 func NewIconsView(cfg *config.Config) *IconsView {
 	items := []IconItem{
-		{Key: "git_clean", Label: "Git Clean", Description: "Icon when git status is clean"},
-		{Key: "git_dirty", Label: "Git Dirty", Description: "Icon when there are uncommitted changes"},
-		{Key: "directory", Label: "Directory", Description: "Icon for directory name"},
-		{Key: "moon_1", Label: "Moon Phase 1", Description: "First moon phase (0-20%)"},
-		{Key: "moon_2", Label: "Moon Phase 2", Description: "Second moon phase (20-40%)"},
-		{Key: "moon_3", Label: "Moon Phase 3", Description: "Third moon phase (40-60%)"},
-		{Key: "moon_4", Label: "Moon Phase 4", Description: "Fourth moon phase (60-80%)"},
-		{Key: "moon_5", Label: "Moon Phase 5", Description: "Fifth moon phase (80-100%)"},
+		{Key: "git_clean", Label: "Git Clean", Description: "Icon when git status is clean", Value: &cfg.Icons.GitClean},
+		{Key: "git_dirty", Label: "Git Dirty", Description: "Icon when there are uncommitted changes", Value: &cfg.Icons.GitDirty},
+		{Key: "git_detached", Label: "Git Detached", Description: "Prefix for the commit on a detached HEAD", Value: &cfg.Icons.GitDetached},
+		{Key: "git_ahead", Label: "Git Ahead", Description: "Prefix for commits ahead of the upstream", Value: &cfg.Icons.GitAhead},
+		{Key: "git_behind", Label: "Git Behind", Description: "Prefix for commits behind the upstream", Value: &cfg.Icons.GitBehind},
+		{Key: "git_staged", Label: "Git Staged", Description: "Prefix for the staged file count", Value: &cfg.Icons.GitStaged},
+		{Key: "git_modified", Label: "Git Modified", Description: "Prefix for the modified file count", Value: &cfg.Icons.GitModified},
+		{Key: "git_untracked", Label: "Git Untracked", Description: "Prefix for the untracked file count", Value: &cfg.Icons.GitUntracked},
+		{Key: "git_stash", Label: "Git Stash", Description: "Prefix for the stash count", Value: &cfg.Icons.GitStash},
+		{Key: "git_operation", Label: "Git Operation", Description: "Icon for a rebase, merge, cherry-pick or bisect in progress", Value: &cfg.Icons.GitOperation},
+		{Key: "git_stale", Label: "Git Stale", Description: "Marker shown when git was too slow and the status is the last known one", Value: &cfg.Icons.GitStale},
+		{Key: "directory", Label: "Directory", Description: "Icon for directory name", Value: &cfg.Icons.Directory},
+		{Key: "moon_1", Label: "Moon Phase 1", Description: "First moon phase", Moon: 0},
+		{Key: "moon_2", Label: "Moon Phase 2", Description: "Second moon phase", Moon: 1},
+		{Key: "moon_3", Label: "Moon Phase 3", Description: "Third moon phase", Moon: 2},
+		{Key: "moon_4", Label: "Moon Phase 4", Description: "Fourth moon phase", Moon: 3},
+		{Key: "moon_5", Label: "Moon Phase 5", Description: "Fifth moon phase", Moon: 4},
+		{Key: "budget", Label: "Budget", Description: "Icon for the session budget", Value: &cfg.Icons.Budget},
+		{Key: "cost", Label: "Cost", Description: "Icon for the session cost", Value: &cfg.Icons.Cost},
+		{Key: "duration", Label: "Duration", Description: "Icon for the session duration", Value: &cfg.Icons.Duration},
+		{Key: "api_time", Label: "API Time", Description: "Icon for time spent waiting on the model", Value: &cfg.Icons.APITime},
+		{Key: "lines", Label: "Lines Changed", Description: "Icon for lines added and removed", Value: &cfg.Icons.Lines},
 	}
 
 	// Initialize text inputs
@@ -58,28 +78,50 @@ func NewIconsView(cfg *config.Config) *IconsView {
 
 // LoadFromConfig populates inputs from config
 func (v *IconsView) LoadFromConfig() {
-	v.Items[0].Input.SetValue(v.Config.Icons.GitClean)
-	v.Items[1].Input.SetValue(v.Config.Icons.GitDirty)
-	v.Items[2].Input.SetValue(v.Config.Icons.Directory)
-
-	for i := 0; i < 5 && i < len(v.Config.Icons.Moons); i++ {
-		v.Items[3+i].Input.SetValue(v.Config.Icons.Moons[i])
+	for i := range v.Items {
+		item := &v.Items[i]
+		switch {
+		case item.Value != nil:
+			item.Input.SetValue(*item.Value)
+		case item.Moon < len(v.Config.Icons.Moons):
+			item.Input.SetValue(v.Config.Icons.Moons[item.Moon])
+		}
 	}
 }
 
 // SaveToConfig writes inputs back to config
 func (v *IconsView) SaveToConfig() {
-	v.Config.Icons.GitClean = v.Items[0].Input.Value()
-	v.Config.Icons.GitDirty = v.Items[1].Input.Value()
-	v.Config.Icons.Directory = v.Items[2].Input.Value()
-
 	moons := make([]string, 5)
-	for i := 0; i < 5; i++ {
-		moons[i] = v.Items[3+i].Input.Value()
+	for _, item := range v.Items {
+		if item.Value != nil {
+			*item.Value = item.Input.Value()
+		} else {
+			moons[item.Moon] = item.Input.Value()
+		}
 	}
 	v.Config.Icons.Moons = moons
 }
 
+// CyclePreset switches to the next preset, e.g. Nerd Font glyphs. Icons the
+// user customized are kept, so only the preset's icons change.
+func (v *IconsView) CyclePreset() {
+	from := v.Preset
+	if from == "" {
+		from = v.Config.Icons.ClosestPreset()
+	}
+	presets := config.IconPresets()
+	idx := 0
+	for i, name := range presets {
+		if name == from {
+			idx = i
+			break
+		}
+	}
+	v.Preset = presets[(idx+1)%len(presets)]
+	v.Kept = v.Config.Icons.SwitchPreset(from, v.Preset)
+	v.LoadFromConfig()
+}
+
 // Up moves selection up
 func (v *IconsView) Up() {
 	if !v.Editing {
@@ -118,6 +160,36 @@ func (v *IconsView) CurrentInput() *textinput.Model {
 	return &v.Items[v.Selected].Input
 }
 
+// describe returns the item description, with the current range for moon phases
+func (v *IconsView) describe(i int) string {
+	item := v.Items[i]
+	if item.Value != nil {
+		return item.Description
+	}
+	lo, hi := v.Config.Thresholds.PhaseRange(item.Moon)
+	return fmt.Sprintf("%s (%d-%d%%)", item.Description, lo, hi)
+}
+
+// errors renders the validation errors of an item. Problems with the moon
+// list as a whole are shown on the first phase.
+func (v *IconsView) errors(errs []config.FieldError, i int) string {
+	item := v.Items[i]
+	if item.Value != nil {
+		return fieldErrors(errs, "icons."+item.Key)
+	}
+	out := fieldErrors(errs, fmt.Sprintf("icons.moons[%d]", item.Moon))
+	if item.Moon == 0 {
+		var list []config.FieldError
+		for _, e := range errs {
+			if e.Path == "icons.moons" {
+				list = append(list, e)
+			}
+		}
+		out = fieldErrors(list, "icons.moons") + out
+	}
+	return out
+}
+
 // Render returns the icons view string
 func (v *IconsView) Render() string {
 	var b strings.Builder
@@ -147,8 +219,22 @@ func (v *IconsView) Render() string {
 
 	b.WriteString(titleStyle.Render("Icons & Emojis"))
 	b.WriteString("\n\n")
+	if v.Preset != "" {
+		note := "  Preset: " + v.Preset
+		if v.Kept > 0 {
+			note += fmt.Sprintf(" (%d custom icons kept)", v.Kept)
+		}
+		b.WriteString(descStyle.Render(note) + "\n\n")
+	}
 
-	for i, item := range v.Items {
+	errs := v.Config.Validate()
+	overrides := v.Project.Overrides()
+	start, end := visibleRange(v.Selected, len(v.Items))
+	if start > 0 {
+		b.WriteString(descStyle.Render("  ↑ more") + "\n")
+	}
+	for i := start; i < end; i++ {
+		item := v.Items[i]
 		var label string
 		if i == v.Selected {
 			label = selectedStyle.Render(item.Label)
@@ -162,14 +248,23 @@ func (v *IconsView) Render() string {
 		} else {
 			value = valueStyle.Render(item.Input.Value())
 		}
+		if item.Value != nil {
+			value += overrideMark(overrides, "icons."+item.Key)
+		} else {
+			value += overrideMark(overrides, "icons.moons")
+		}
 
 		b.WriteString("  " + label + ": " + value)
 		if i == v.Selected {
 			b.WriteString("\n")
-			b.WriteString(descStyle.Render("      " + item.Description))
+			b.WriteString(descStyle.Render("      " + v.describe(i)))
 		}
+		b.WriteString(v.errors(errs, i))
 		b.WriteString("\n")
 	}
+	if end < len(v.Items) {
+		b.WriteString(descStyle.Render("  ↓ more") + "\n")
+	}
 
 	b.WriteString("\n")
 	if v.Editing {
@@ -177,7 +272,7 @@ func (v *IconsView) Render() string {
 			"  [enter] Save  [esc] Cancel"))
 	} else {
 		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render(
-			"  [enter/e] Edit  [esc] Back"))
+			"  [enter/e] Edit  [p] Next preset (emoji, Nerd Font)  [esc] Back"))
 	}
 
 	return b.String()
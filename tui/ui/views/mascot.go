@@ -35,6 +35,7 @@ type MascotView struct {
 	ThresholdInput   textinput.Model
 	SpeedInput       textinput.Model
 	Config           *config.Config
+	Project          *Project // Set when editing a project overlay
 }
 
 // NewMascotView creates a new mascot view
@@ -398,6 +399,29 @@ func (v *MascotView) CurrentInput() *textinput.Model {
 	return nil
 }
 
+// paths returns the JSON paths of a category's emoji list and speed
+func (v *MascotView) paths(cat MascotCategory) (emojis, speed string) {
+	if period := strings.TrimPrefix(cat.Key, "time_"); period != cat.Key {
+		return "mascot.time_based." + period, "mascot.time_based.speed"
+	}
+	return "mascot." + cat.Key + ".emojis", "mascot." + cat.Key + ".speed"
+}
+
+// overrideMark marks a category the project overlay changes; the time of day
+// moods share their switches and speed
+func (v *MascotView) overrideMark(overrides map[string]bool, cat MascotCategory) string {
+	emojis, _ := v.paths(cat)
+	if !strings.HasPrefix(cat.Key, "time_") {
+		return overrideMark(overrides, "mascot."+cat.Key)
+	}
+	for _, path := range []string{emojis, "mascot.time_based.enabled", "mascot.time_based.animate", "mascot.time_based.speed"} {
+		if mark := overrideMark(overrides, path); mark != "" {
+			return mark
+		}
+	}
+	return ""
+}
+
 // Render returns the mascot view string
 func (v *MascotView) Render() string {
 	var b strings.Builder
@@ -433,6 +457,9 @@ func (v *MascotView) Render() string {
 	b.WriteString(titleStyle.Render("Mascot Settings"))
 	b.WriteString("\n\n")
 
+	errs := v.Config.Validate()
+	overrides := v.Project.Overrides()
+
 	if !v.InCategory {
 		// Show category list
 		for i, cat := range v.Categories {
@@ -450,11 +477,16 @@ func (v *MascotView) Render() string {
 				label = normalStyle.Render(cat.Label)
 			}
 
-			b.WriteString("  " + checkbox + " " + label)
+			b.WriteString("  " + checkbox + " " + label + v.overrideMark(overrides, cat))
 			if i == v.Selected {
 				b.WriteString("\n")
 				b.WriteString(descStyle.Render("      " + cat.Description))
 			}
+			emojis, speed := v.paths(cat)
+			b.WriteString(fieldErrors(errs, emojis) + fieldErrors(errs, speed))
+			if cat.Threshold != nil {
+				b.WriteString(fieldErrors(errs, "mascot."+cat.Key+".threshold"))
+			}
 			b.WriteString("\n")
 		}
 
@@ -464,6 +496,7 @@ func (v *MascotView) Render() string {
 		// Show category details
 		cat := v.Categories[v.Selected]
 		emojiOffset := v.getEmojiOffset(cat)
+		emojisPath, speedPath := v.paths(cat)
 		b.WriteString(titleStyle.Render("  " + cat.Label))
 		b.WriteString("\n\n")
 
@@ -499,7 +532,8 @@ func (v *MascotView) Render() string {
 			} else {
 				thresholdLabel = normalStyle.Render(thresholdLabel)
 			}
-			b.WriteString("    " + thresholdLabel + ": " + thresholdValue + "\n")
+			b.WriteString("    " + thresholdLabel + ": " + thresholdValue)
+			b.WriteString(fieldErrors(errs, "mascot."+cat.Key+".threshold") + "\n")
 			itemIdx++
 		}
 
@@ -532,7 +566,8 @@ func (v *MascotView) Render() string {
 		} else {
 			speedLabel = normalStyle.Render(speedLabel)
 		}
-		b.WriteString("    " + speedLabel + ": " + speedValue + "\n")
+		b.WriteString("    " + speedLabel + ": " + speedValue)
+		b.WriteString(fieldErrors(errs, speedPath) + "\n")
 
 		// Emojis (Animation Frames)
 		b.WriteString("\n")
@@ -541,6 +576,9 @@ func (v *MascotView) Render() string {
 		} else {
 			b.WriteString(normalStyle.Render("    Emojis:") + "\n")
 		}
+		if list := fieldErrors(errs, emojisPath); list != "" {
+			b.WriteString(strings.TrimPrefix(list, "\n") + "\n")
+		}
 		for i, emoji := range *cat.Emojis {
 			var emojiDisplay string
 			if v.EditingEmoji && v.SubSelected == i+emojiOffset {
@@ -24,13 +24,15 @@ type MenuView struct {
 func NewMenuView() *MenuView {
 	return &MenuView{
 		Items: []MenuItem{
-			{Label: "Sections", Description: "Toggle which sections are displayed"},
+			{Label: "Sections", Description: "Toggle and reorder the displayed sections"},
 			{Label: "Icons & Emojis", Description: "Customize icons and emojis"},
+			{Label: "Colors", Description: "Pick section colors: names, 256-color indexes or #RRGGBB"},
 			{Label: "Mascot Settings", Description: "Configure mascot moods and triggers"},
 			{Label: "Display Options", Description: "Separator and formatting settings"},
 			{Label: "Notifications", Description: "Configure alerts, sounds, and notification triggers"},
+			{Label: "Backups", Description: "Compare and restore earlier saved versions"},
 			{IsSeparator: true},
-			{Label: "Save & Apply", Description: "Save config and install statusline to ~/.claude/"},
+			{Label: "Save & Apply", Description: "Save config and point ~/.claude/settings.json at the renderer"},
 			{Label: "Save Config Only", Description: "Save config without installing globally"},
 		},
 		Selected: 0,
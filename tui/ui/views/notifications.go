@@ -11,6 +11,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
 	"statusline-config/config"
+	"statusline-config/render"
 )
 
 // NotificationCategory represents a notification type category
@@ -45,11 +46,16 @@ type NotificationsView struct {
 	SelectingSound   bool
 	EditingThreshold bool
 	ThresholdInput   textinput.Model
+	thresholdTarget  *int // set when editing a threshold other than the context one
 	EditingTitle     bool
 	TitleInput       textinput.Model
+	EditingText      bool
+	TextInput        textinput.Model
+	textTarget       *string
 	SelectingVolume  bool
 	VolumeOptions    []VolumeOption
 	VolumeSelected   int
+	Project          *Project // Set when editing a project overlay
 }
 
 // NewNotificationsView creates a new notifications view
@@ -64,6 +70,10 @@ func NewNotificationsView(cfg *config.Config) *NotificationsView {
 	titleInput.CharLimit = 100
 	titleInput.Width = 40
 
+	textInput := textinput.New()
+	textInput.CharLimit = 100
+	textInput.Width = 40
+
 	v := &NotificationsView{
 		Config: cfg,
 		VolumeOptions: []VolumeOption{
@@ -81,6 +91,7 @@ func NewNotificationsView(cfg *config.Config) *NotificationsView {
 		Selected:       0,
 		ThresholdInput: ti,
 		TitleInput:     titleInput,
+		TextInput:      textInput,
 	}
 	v.loadSoundOptions()
 	v.selectCurrentSound()
@@ -219,9 +230,13 @@ func (n *NotificationsView) getMaxSubItems() int {
 	cat := n.Categories[n.Selected]
 	switch cat.Key {
 	case "desktop":
-		return 7 // enabled, on_context_panic, threshold, title, sound, sound_path, sound_volume
+		return 9 // enabled, on_context_panic, threshold, title, sound, sound_path, sound_volume, on_session_limit, session_threshold
 	case "terminal_title":
-		return 5 // enabled, show_model, show_context, alert_on_panic, threshold
+		return 8 // enabled, show_model, show_context, show_branch, alert_on_panic, threshold, panic_prefix, template
+	case "terminal_bell":
+		return 5 // enabled, on_context_panic, threshold, on_session_limit, session_threshold
+	case "tmux":
+		return 8 // enabled, on_context_panic, threshold, display_message, set_window_style, alert_style, on_session_limit, session_threshold
 	default:
 		return 3 // enabled, on_context_panic, threshold
 	}
@@ -249,7 +264,12 @@ func (n *NotificationsView) Enter() {
 		if _, err := parseThreshold(n.ThresholdInput.Value()); err == nil {
 			threshold, _ = parseThreshold(n.ThresholdInput.Value())
 		}
-		n.setThreshold(threshold)
+		if n.thresholdTarget != nil {
+			*n.thresholdTarget = threshold
+			n.thresholdTarget = nil
+		} else {
+			n.setThreshold(threshold)
+		}
 		n.EditingThreshold = false
 		return
 	}
@@ -260,6 +280,11 @@ func (n *NotificationsView) Enter() {
 		return
 	}
 
+	if n.EditingText {
+		*n.textTarget = n.TextInput.Value()
+		n.EditingText = false
+		return
+	}
 
 	if !n.InCategory {
 		n.InCategory = true
@@ -272,8 +297,10 @@ func (n *NotificationsView) Enter() {
 	switch cat.Key {
 	case "desktop":
 		n.handleDesktopAction()
-	case "terminal_bell", "blinking_text", "tmux":
+	case "terminal_bell", "blinking_text":
 		n.handleBasicAction()
+	case "tmux":
+		n.handleTmuxAction()
 	case "terminal_title":
 		n.handleTerminalTitleAction()
 	}
@@ -301,6 +328,10 @@ func (n *NotificationsView) handleDesktopAction() {
 	case 6: // sound volume
 		n.SelectingVolume = true
 		n.selectCurrentVolume()
+	case 7: // on_session_limit
+		n.Config.Notifications.Desktop.OnSessionLimit = !n.Config.Notifications.Desktop.OnSessionLimit
+	case 8: // session_threshold
+		n.editSessionThreshold(&n.Config.Notifications.Desktop.SessionThreshold)
 	}
 }
 
@@ -322,9 +353,47 @@ func (n *NotificationsView) handleBasicAction() {
 		n.ThresholdInput.SetValue(intToStr(n.getThreshold()))
 		n.ThresholdInput.Focus()
 		n.EditingThreshold = true
+	case 3: // on_session_limit (terminal bell only)
+		n.Config.Notifications.TerminalBell.OnSessionLimit = !n.Config.Notifications.TerminalBell.OnSessionLimit
+	case 4: // session_threshold
+		n.editSessionThreshold(&n.Config.Notifications.TerminalBell.SessionThreshold)
 	}
 }
 
+// editSessionThreshold starts editing the % of the session budget that triggers an alert
+func (n *NotificationsView) editSessionThreshold(target *int) {
+	n.thresholdTarget = target
+	n.ThresholdInput.SetValue(intToStr(*target))
+	n.ThresholdInput.Focus()
+	n.EditingThreshold = true
+}
+
+func (n *NotificationsView) handleTmuxAction() {
+	switch n.SubSelected {
+	case 0, 1, 2: // enabled, on_context_panic, threshold
+		n.handleBasicAction()
+	case 3: // display_message
+		n.Config.Notifications.Tmux.DisplayMessage = !n.Config.Notifications.Tmux.DisplayMessage
+	case 4: // set_window_style
+		n.Config.Notifications.Tmux.SetWindowStyle = !n.Config.Notifications.Tmux.SetWindowStyle
+	case 5: // alert_style
+		n.editText(&n.Config.Notifications.Tmux.AlertStyle, "bg=red,fg=white,bold")
+	case 6: // on_session_limit
+		n.Config.Notifications.Tmux.OnSessionLimit = !n.Config.Notifications.Tmux.OnSessionLimit
+	case 7: // session_threshold
+		n.editSessionThreshold(&n.Config.Notifications.Tmux.SessionThreshold)
+	}
+}
+
+// editText starts editing a free-form text setting
+func (n *NotificationsView) editText(target *string, placeholder string) {
+	n.textTarget = target
+	n.TextInput.Placeholder = placeholder
+	n.TextInput.SetValue(*target)
+	n.TextInput.Focus()
+	n.EditingText = true
+}
+
 func (n *NotificationsView) handleTerminalTitleAction() {
 	switch n.SubSelected {
 	case 0: // enabled
@@ -333,12 +402,18 @@ func (n *NotificationsView) handleTerminalTitleAction() {
 		n.Config.Notifications.TerminalTitle.ShowModel = !n.Config.Notifications.TerminalTitle.ShowModel
 	case 2: // show_context
 		n.Config.Notifications.TerminalTitle.ShowContext = !n.Config.Notifications.TerminalTitle.ShowContext
-	case 3: // alert_on_panic
+	case 3: // show_branch
+		n.Config.Notifications.TerminalTitle.ShowBranch = !n.Config.Notifications.TerminalTitle.ShowBranch
+	case 4: // alert_on_panic
 		n.Config.Notifications.TerminalTitle.AlertOnPanic = !n.Config.Notifications.TerminalTitle.AlertOnPanic
-	case 4: // threshold
+	case 5: // threshold
 		n.ThresholdInput.SetValue(intToStr(n.Config.Notifications.TerminalTitle.ContextThreshold))
 		n.ThresholdInput.Focus()
 		n.EditingThreshold = true
+	case 6: // panic_prefix
+		n.editText(&n.Config.Notifications.TerminalTitle.PanicPrefix, "⚠ ")
+	case 7: // template
+		n.editText(&n.Config.Notifications.TerminalTitle.Template, "{model} · {context} · {branch}")
 	}
 }
 
@@ -383,12 +458,17 @@ func (n *NotificationsView) Back() bool {
 	}
 	if n.EditingThreshold {
 		n.EditingThreshold = false
+		n.thresholdTarget = nil
 		return false
 	}
 	if n.EditingTitle {
 		n.EditingTitle = false
 		return false
 	}
+	if n.EditingText {
+		n.EditingText = false
+		return false
+	}
 	if n.SelectingVolume {
 		n.SelectingVolume = false
 		return false
@@ -408,6 +488,9 @@ func (n *NotificationsView) CurrentInput() *textinput.Model {
 	if n.EditingTitle {
 		return &n.TitleInput
 	}
+	if n.EditingText {
+		return &n.TextInput
+	}
 	return nil
 }
 
@@ -469,6 +552,8 @@ func (n *NotificationsView) Render() string {
 }
 
 func (n *NotificationsView) renderCategoryList(b *strings.Builder, selectedStyle, normalStyle, checkStyle, uncheckStyle, descStyle lipgloss.Style) string {
+	errs := n.Config.Validate()
+	overrides := n.Project.Overrides()
 	for i, cat := range n.Categories {
 		var checkbox string
 		if *cat.Enabled {
@@ -484,11 +569,12 @@ func (n *NotificationsView) renderCategoryList(b *strings.Builder, selectedStyle
 			label = normalStyle.Render(cat.Label)
 		}
 
-		b.WriteString("  " + checkbox + " " + label)
+		b.WriteString("  " + checkbox + " " + label + overrideMark(overrides, "notifications."+cat.Key))
 		if i == n.Selected {
 			b.WriteString("\n")
 			b.WriteString(descStyle.Render("      " + cat.Description))
 		}
+		b.WriteString(fieldErrors(errs, "notifications."+cat.Key))
 		b.WriteString("\n")
 	}
 
@@ -507,6 +593,7 @@ func (n *NotificationsView) renderCategoryDetail(b *strings.Builder, selectedSty
 		Bold(true)
 
 	b.WriteString(subTitleStyle.Render(cat.Label + " Settings"))
+	b.WriteString(fieldErrors(n.Config.Validate(), "notifications."+cat.Key))
 	b.WriteString("\n\n")
 
 	switch cat.Key {
@@ -514,6 +601,8 @@ func (n *NotificationsView) renderCategoryDetail(b *strings.Builder, selectedSty
 		n.renderDesktopSettings(b, selectedStyle, normalStyle, checkStyle, uncheckStyle, highlightStyle)
 	case "terminal_title":
 		n.renderTerminalTitleSettings(b, selectedStyle, normalStyle, checkStyle, uncheckStyle, highlightStyle)
+	case "tmux":
+		n.renderTmuxSettings(b, selectedStyle, normalStyle, checkStyle, uncheckStyle, highlightStyle)
 	default:
 		n.renderBasicSettings(b, selectedStyle, normalStyle, checkStyle, uncheckStyle, highlightStyle)
 	}
@@ -522,7 +611,7 @@ func (n *NotificationsView) renderCategoryDetail(b *strings.Builder, selectedSty
 	if n.EditingThreshold {
 		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render(
 			"  [enter] Save  [esc] Cancel"))
-	} else if n.EditingTitle {
+	} else if n.EditingTitle || n.EditingText {
 		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render(
 			"  [enter] Save  [esc] Cancel"))
 	} else {
@@ -546,6 +635,8 @@ func (n *NotificationsView) renderDesktopSettings(b *strings.Builder, selectedSt
 		{"Sound Enabled", n.Config.Notifications.Desktop.Sound, ""},
 		{"Sound File", false, n.getCurrentSoundName()},
 		{"Sound Volume", false, n.getCurrentVolumeName()},
+		{"Trigger on Session Budget", n.Config.Notifications.Desktop.OnSessionLimit, ""},
+		{"Budget Threshold", false, intToStr(n.Config.Notifications.Desktop.SessionThreshold) + "%"},
 	}
 
 	for i, item := range items {
@@ -556,10 +647,10 @@ func (n *NotificationsView) renderDesktopSettings(b *strings.Builder, selectedSt
 		}
 
 		if item.value != "" {
-			if i == 2 && n.SubSelected == 2 && n.EditingThreshold {
-				line = style.Render("    " + item.label + ": ") + n.ThresholdInput.View()
+			if i == n.SubSelected && n.EditingThreshold {
+				line = style.Render("    "+item.label+": ") + n.ThresholdInput.View()
 			} else if i == 3 && n.SubSelected == 3 && n.EditingTitle {
-				line = style.Render("    " + item.label + ": ") + n.TitleInput.View()
+				line = style.Render("    "+item.label+": ") + n.TitleInput.View()
 			} else {
 				line = style.Render("    "+item.label+": ") + highlightStyle.Render(item.value)
 			}
@@ -578,14 +669,16 @@ func (n *NotificationsView) renderDesktopSettings(b *strings.Builder, selectedSt
 
 func (n *NotificationsView) renderBasicSettings(b *strings.Builder, selectedStyle, normalStyle, checkStyle, uncheckStyle, highlightStyle lipgloss.Style) {
 	cat := n.Categories[n.Selected]
-	var enabled, onPanic bool
-	var threshold int
+	var enabled, onPanic, onLimit bool
+	var threshold, sessionThreshold int
 
 	switch cat.Key {
 	case "terminal_bell":
 		enabled = n.Config.Notifications.TerminalBell.Enabled
 		onPanic = n.Config.Notifications.TerminalBell.OnContextPanic
 		threshold = n.Config.Notifications.TerminalBell.ContextThreshold
+		onLimit = n.Config.Notifications.TerminalBell.OnSessionLimit
+		sessionThreshold = n.Config.Notifications.TerminalBell.SessionThreshold
 	case "blinking_text":
 		enabled = n.Config.Notifications.BlinkingText.Enabled
 		onPanic = n.Config.Notifications.BlinkingText.OnContextPanic
@@ -604,6 +697,58 @@ func (n *NotificationsView) renderBasicSettings(b *strings.Builder, selectedStyl
 		{"Enabled", enabled, ""},
 		{"Trigger on Context Panic", onPanic, ""},
 		{"Context Threshold", false, intToStr(threshold) + "%"},
+		{"Trigger on Session Budget", onLimit, ""},
+		{"Budget Threshold", false, intToStr(sessionThreshold) + "%"},
+	}
+
+	// Only the terminal bell also alerts on the session budget
+	items = items[:n.getMaxSubItems()]
+
+	for i, item := range items {
+		var line string
+		style := normalStyle
+		if i == n.SubSelected {
+			style = selectedStyle
+		}
+
+		if item.value != "" {
+			if i == n.SubSelected && n.EditingThreshold {
+				line = style.Render("    "+item.label+": ") + n.ThresholdInput.View()
+			} else {
+				line = style.Render("    "+item.label+": ") + highlightStyle.Render(item.value)
+			}
+		} else {
+			var checkbox string
+			if item.enabled {
+				checkbox = checkStyle.Render("[x]")
+			} else {
+				checkbox = uncheckStyle.Render("[ ]")
+			}
+			line = "  " + checkbox + " " + style.Render(item.label)
+		}
+		b.WriteString(line + "\n")
+	}
+}
+
+func (n *NotificationsView) renderTmuxSettings(b *strings.Builder, selectedStyle, normalStyle, checkStyle, uncheckStyle, highlightStyle lipgloss.Style) {
+	cfg := n.Config.Notifications.Tmux
+	alertStyle := cfg.AlertStyle
+	if alertStyle == "" {
+		alertStyle = "(default)"
+	}
+	items := []struct {
+		label   string
+		enabled bool
+		value   string
+	}{
+		{"Enabled", cfg.Enabled, ""},
+		{"Trigger on Context Panic", cfg.OnContextPanic, ""},
+		{"Context Threshold", false, intToStr(cfg.ContextThreshold) + "%"},
+		{"Display Message", cfg.DisplayMessage, ""},
+		{"Highlight Window", cfg.SetWindowStyle, ""},
+		{"Alert Style", false, truncateStr(alertStyle, 30)},
+		{"Trigger on Session Budget", cfg.OnSessionLimit, ""},
+		{"Budget Threshold", false, intToStr(cfg.SessionThreshold) + "%"},
 	}
 
 	for i, item := range items {
@@ -614,8 +759,10 @@ func (n *NotificationsView) renderBasicSettings(b *strings.Builder, selectedStyl
 		}
 
 		if item.value != "" {
-			if i == 2 && n.SubSelected == 2 && n.EditingThreshold {
-				line = style.Render("    " + item.label + ": ") + n.ThresholdInput.View()
+			if i == n.SubSelected && n.EditingThreshold {
+				line = style.Render("    "+item.label+": ") + n.ThresholdInput.View()
+			} else if i == 5 && n.SubSelected == 5 && n.EditingText {
+				line = style.Render("    "+item.label+": ") + n.TextInput.View()
 			} else {
 				line = style.Render("    "+item.label+": ") + highlightStyle.Render(item.value)
 			}
@@ -634,6 +781,14 @@ func (n *NotificationsView) renderBasicSettings(b *strings.Builder, selectedStyl
 
 func (n *NotificationsView) renderTerminalTitleSettings(b *strings.Builder, selectedStyle, normalStyle, checkStyle, uncheckStyle, highlightStyle lipgloss.Style) {
 	cfg := n.Config.Notifications.TerminalTitle
+	prefix := cfg.PanicPrefix
+	if prefix == "" {
+		prefix = "(default)"
+	}
+	template := cfg.Template
+	if template == "" {
+		template = "(use flags)"
+	}
 	items := []struct {
 		label   string
 		enabled bool
@@ -642,8 +797,11 @@ func (n *NotificationsView) renderTerminalTitleSettings(b *strings.Builder, sele
 		{"Enabled", cfg.Enabled, ""},
 		{"Show Model", cfg.ShowModel, ""},
 		{"Show Context", cfg.ShowContext, ""},
+		{"Show Branch", cfg.ShowBranch, ""},
 		{"Alert on Panic", cfg.AlertOnPanic, ""},
 		{"Context Threshold", false, intToStr(cfg.ContextThreshold) + "%"},
+		{"Panic Prefix", false, truncateStr(prefix, 30)},
+		{"Template", false, truncateStr(template, 30)},
 	}
 
 	for i, item := range items {
@@ -654,8 +812,10 @@ func (n *NotificationsView) renderTerminalTitleSettings(b *strings.Builder, sele
 		}
 
 		if item.value != "" {
-			if i == 4 && n.SubSelected == 4 && n.EditingThreshold {
-				line = style.Render("    " + item.label + ": ") + n.ThresholdInput.View()
+			if i == 5 && n.SubSelected == 5 && n.EditingThreshold {
+				line = style.Render("    "+item.label+": ") + n.ThresholdInput.View()
+			} else if i >= 6 && n.SubSelected == i && n.EditingText {
+				line = style.Render("    "+item.label+": ") + n.TextInput.View()
 			} else {
 				line = style.Render("    "+item.label+": ") + highlightStyle.Render(item.value)
 			}
@@ -670,6 +830,14 @@ func (n *NotificationsView) renderTerminalTitleSettings(b *strings.Builder, sele
 		}
 		b.WriteString(line + "\n")
 	}
+
+	// Show the title the sample session would get, with placeholders explained
+	b.WriteString("\n")
+	b.WriteString(normalStyle.Render("    Title: ") + highlightStyle.Render(render.Title(render.PreviewContext(n.Config))))
+	b.WriteString("\n")
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Italic(true).Render(
+		"    Template placeholders: {model} {context} {branch} {dir}"))
+	b.WriteString("\n")
 }
 
 func (n *NotificationsView) renderSoundSelector(b *strings.Builder, selectedStyle, normalStyle, highlightStyle lipgloss.Style) string {
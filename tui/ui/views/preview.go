@@ -1,10 +1,12 @@
 package views
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"statusline-config/config"
+	"statusline-config/render"
 )
 
 // PreviewView renders a live preview of the statusline
@@ -19,90 +21,79 @@ func NewPreviewView(cfg *config.Config) *PreviewView {
 
 // Render returns the preview string
 func (v *PreviewView) Render() string {
-	var parts []string
+	// Run the real renderer over sample data so the preview matches the statusline
+	preview := render.Render(render.PreviewContext(v.Config))
 
-	cfg := v.Config
+	// Style the preview without a border box for cleaner full-width display
+	previewStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Padding(0, 1)
 
-	// Git section
-	if cfg.EnabledSections.Git {
-		parts = append(parts, cfg.Icons.GitClean+" main")
-	}
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
 
-	// Directory section
-	if cfg.EnabledSections.Directory {
-		parts = append(parts, cfg.Icons.Directory+" project")
+	// Show what survives in a narrow tmux split, where low-priority segments are dropped
+	narrowCtx := render.PreviewContext(v.Config)
+	if narrowCtx.MaxWidth <= 0 || narrowCtx.MaxWidth > narrowWidth {
+		narrowCtx.MaxWidth = narrowWidth
 	}
+	narrow := render.Render(narrowCtx)
+
+	return labelStyle.Render("Preview:") + "\n" + previewStyle.Render(preview) + "\n" +
+		labelStyle.Render(fmt.Sprintf("At %d columns:", narrowCtx.MaxWidth)) + "\n" + previewStyle.Render(narrow)
+}
+
+// narrowWidth is the terminal width of the narrow preview
+const narrowWidth = 50
+
+// gaugeSamples are the percentages shown in the gauge comparison
+var gaugeSamples = []int{0, 25, 50, 75, 100}
+
+// RenderGauges shows every gauge style at a range of usage levels side by side
+func (v *PreviewView) RenderGauges() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#10B981")).
+		Bold(true)
 
-	// Model section
-	if cfg.EnabledSections.Model {
-		parts = append(parts, "Sonnet")
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#9CA3AF"))
+
+	var header strings.Builder
+	header.WriteString(fmt.Sprintf("%-10s", ""))
+	for _, pct := range gaugeSamples {
+		header.WriteString(fmt.Sprintf("%-*s", gaugeColumnWidth(v.Config), fmt.Sprintf("%d%%", pct)))
 	}
 
-	// Context moons
-	if cfg.EnabledSections.ContextMoons {
-		moons := ""
-		if len(cfg.Icons.Moons) >= 3 {
-			moons = cfg.Icons.Moons[0] + cfg.Icons.Moons[1] + cfg.Icons.Moons[2]
-		} else {
-			moons = "🌑🌘🌗"
-		}
-		moonPart := moons
-		if cfg.EnabledSections.TokenCount {
-			moonPart += " 12k"
-		}
-		if cfg.EnabledSections.Percentage {
-			moonPart += " (45%)"
+	lines := []string{labelStyle.Render("Gauge styles:"), labelStyle.Render(header.String())}
+	for _, style := range config.GaugeStyles() {
+		nameStyle := normalStyle
+		if style == v.Config.Display.Gauge.Style {
+			nameStyle = selectedStyle
 		}
-		parts = append(parts, moonPart)
-	} else {
-		// Show tokens/percentage without moons
-		if cfg.EnabledSections.TokenCount || cfg.EnabledSections.Percentage {
-			tokenPart := ""
-			if cfg.EnabledSections.TokenCount {
-				tokenPart = "12k"
-			}
-			if cfg.EnabledSections.Percentage {
-				if tokenPart != "" {
-					tokenPart += " "
-				}
-				tokenPart += "(45%)"
-			}
-			parts = append(parts, tokenPart)
+		var row strings.Builder
+		row.WriteString(nameStyle.Render(fmt.Sprintf("%-10s", style)))
+		for _, pct := range gaugeSamples {
+			gauge := render.Gauge(v.Config, style, pct)
+			row.WriteString(gauge)
+			row.WriteString(strings.Repeat(" ", gaugeColumnWidth(v.Config)-lipgloss.Width(gauge)))
 		}
+		lines = append(lines, row.String())
 	}
+	return strings.Join(lines, "\n")
+}
 
-	// Mascot
-	if cfg.EnabledSections.Mascot {
-		// Show animation frames if enabled, otherwise first emoji
-		var mascotDisplay string
-		if cfg.Mascot.TimeBased.Enabled && len(cfg.Mascot.TimeBased.Afternoon) > 0 {
-			if cfg.Mascot.TimeBased.Animate && len(cfg.Mascot.TimeBased.Afternoon) > 1 {
-				// Show animation sequence preview
-				mascotDisplay = strings.Join(cfg.Mascot.TimeBased.Afternoon, "→")
-			} else {
-				mascotDisplay = cfg.Mascot.TimeBased.Afternoon[0]
-			}
-		} else {
-			mascotDisplay = "💻→⌨️→🖱️→⌨️"
+// gaugeColumnWidth is wide enough for the widest gauge plus a gap
+func gaugeColumnWidth(cfg *config.Config) int {
+	width := 6
+	for _, style := range config.GaugeStyles() {
+		if w := lipgloss.Width(render.Gauge(cfg, style, 100)) + 2; w > width {
+			width = w
 		}
-		parts = append(parts, mascotDisplay)
 	}
-
-	separator := cfg.Display.Separator
-	if separator == "" {
-		separator = " | "
-	}
-
-	preview := strings.Join(parts, separator)
-
-	// Style the preview without a border box for cleaner full-width display
-	previewStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Padding(0, 1)
-
-	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6B7280")).
-		Italic(true)
-
-	return labelStyle.Render("Preview:") + "\n" + previewStyle.Render(preview)
+	return width
 }
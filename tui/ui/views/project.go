@@ -0,0 +1,45 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"statusline-config/config"
+)
+
+// Project is a project overlay being edited over the global config
+type Project struct {
+	Path   string         // The overlay file
+	Loaded *config.Config // The global config with the overlay applied, as last loaded or saved
+	Config *config.Config // The same, as edited
+
+	overrides map[string]bool
+}
+
+// Refresh recomputes the JSON paths the overlay sets; call it after the
+// overlay is loaded, edited or saved
+func (p *Project) Refresh() {
+	p.overrides = config.Overrides(p.Loaded, p.Config, p.Path)
+}
+
+// Overrides returns the JSON paths the overlay sets with the edits made so
+// far, as of the last Refresh, or nil when p is nil
+func (p *Project) Overrides() map[string]bool {
+	if p == nil {
+		return nil
+	}
+	return p.overrides
+}
+
+// overrideMark marks a value set by the project overlay. Values without the
+// mark are inherited from the global config.
+func overrideMark(overrides map[string]bool, path string) string {
+	for p := range overrides {
+		if p == path || strings.HasPrefix(p, path+".") || strings.HasPrefix(path, p+".") {
+			return lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#0EA5E9")).
+				Render(" ● project")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,17 @@
+package views
+
+// maxVisibleItems is how many rows a long list shows before it scrolls
+const maxVisibleItems = 12
+
+// visibleRange returns the [start, end) window of a list of total rows that
+// keeps the selected row in view
+func visibleRange(selected, total int) (start, end int) {
+	if selected >= maxVisibleItems {
+		start = selected - maxVisibleItems + 1
+	}
+	end = start + maxVisibleItems
+	if end > total {
+		end = total
+	}
+	return start, end
+}
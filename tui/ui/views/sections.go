@@ -1,10 +1,12 @@
 package views
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"statusline-config/config"
+	"statusline-config/render"
 )
 
 // SectionItem represents a toggleable section
@@ -12,7 +14,8 @@ type SectionItem struct {
 	Key         string
 	Label       string
 	Description string
-	Enabled     *bool
+	Enabled     bool
+	Priority    int
 }
 
 // SectionsView handles the sections toggle screen
@@ -20,37 +23,29 @@ type SectionsView struct {
 	Items    []SectionItem
 	Selected int
 	Config   *config.Config
+	Project  *Project // Set when editing a project overlay
 }
 
 // NewSectionsView creates a new sections view
 func NewSectionsView(cfg *config.Config) *SectionsView {
-	return &SectionsView{
-		Config: cfg,
-		Items: []SectionItem{
-			{Key: "waiting_indicator", Label: "Waiting Indicator", Description: "Show alert when Claude needs your input", Enabled: &cfg.EnabledSections.WaitingIndicator},
-			{Key: "git", Label: "Git Branch", Description: "Show current git branch and status", Enabled: &cfg.EnabledSections.Git},
-			{Key: "directory", Label: "Directory", Description: "Show current directory name", Enabled: &cfg.EnabledSections.Directory},
-			{Key: "model", Label: "Model Name", Description: "Show Claude model in use", Enabled: &cfg.EnabledSections.Model},
-			{Key: "context_moons", Label: "Context Moons", Description: "Visual moon phases for context usage", Enabled: &cfg.EnabledSections.ContextMoons},
-			{Key: "token_count", Label: "Token Count", Description: "Show token count (e.g., 12k)", Enabled: &cfg.EnabledSections.TokenCount},
-			{Key: "percentage", Label: "Percentage", Description: "Show context usage percentage", Enabled: &cfg.EnabledSections.Percentage},
-			{Key: "mascot", Label: "Mascot", Description: "Show reactive mascot emoji", Enabled: &cfg.EnabledSections.Mascot},
-		},
-		Selected: 0,
-	}
+	s := &SectionsView{Selected: 0}
+	s.UpdateConfig(cfg)
+	return s
 }
 
 // UpdateConfig refreshes the view with new config
 func (s *SectionsView) UpdateConfig(cfg *config.Config) {
 	s.Config = cfg
-	s.Items[0].Enabled = &cfg.EnabledSections.WaitingIndicator
-	s.Items[1].Enabled = &cfg.EnabledSections.Git
-	s.Items[2].Enabled = &cfg.EnabledSections.Directory
-	s.Items[3].Enabled = &cfg.EnabledSections.Model
-	s.Items[4].Enabled = &cfg.EnabledSections.ContextMoons
-	s.Items[5].Enabled = &cfg.EnabledSections.TokenCount
-	s.Items[6].Enabled = &cfg.EnabledSections.Percentage
-	s.Items[7].Enabled = &cfg.EnabledSections.Mascot
+	s.Items = s.Items[:0]
+	for _, seg := range render.Ordered(cfg) {
+		s.Items = append(s.Items, SectionItem{
+			Key:         seg.Name(),
+			Label:       seg.Label,
+			Description: seg.Description,
+			Enabled:     cfg.EnabledSections.Enabled(seg.Name()),
+			Priority:    seg.PriorityOf(cfg),
+		})
+	}
 }
 
 // Up moves selection up
@@ -69,11 +64,63 @@ func (s *SectionsView) Down() {
 	}
 }
 
+// MoveUp moves the selected section one place earlier in the statusline
+func (s *SectionsView) MoveUp() {
+	if s.Selected > 0 {
+		s.swap(s.Selected, s.Selected-1)
+		s.Selected--
+	}
+}
+
+// MoveDown moves the selected section one place later in the statusline
+func (s *SectionsView) MoveDown() {
+	if s.Selected < len(s.Items)-1 {
+		s.swap(s.Selected, s.Selected+1)
+		s.Selected++
+	}
+}
+
+// swap exchanges two items and writes the new order to the config
+func (s *SectionsView) swap(i, j int) {
+	s.Items[i], s.Items[j] = s.Items[j], s.Items[i]
+	order := make([]string, len(s.Items))
+	for i, item := range s.Items {
+		order[i] = item.Key
+	}
+	s.Config.SectionOrder = order
+}
+
 // Toggle toggles the selected item
 func (s *SectionsView) Toggle() {
-	if s.Selected >= 0 && s.Selected < len(s.Items) {
-		*s.Items[s.Selected].Enabled = !*s.Items[s.Selected].Enabled
+	if s.Selected < 0 || s.Selected >= len(s.Items) {
+		return
+	}
+	item := &s.Items[s.Selected]
+	item.Enabled = !item.Enabled
+	if s.Config.EnabledSections == nil {
+		s.Config.EnabledSections = config.EnabledSections{}
 	}
+	s.Config.EnabledSections[item.Key] = item.Enabled
+}
+
+// AdjustPriority raises or lowers the selected section's priority, which
+// decides what is dropped first when the statusline is too wide
+func (s *SectionsView) AdjustPriority(step int) {
+	if s.Selected < 0 || s.Selected >= len(s.Items) {
+		return
+	}
+	item := &s.Items[s.Selected]
+	item.Priority += step
+	if item.Priority < 0 {
+		item.Priority = 0
+	}
+	if item.Priority > 100 {
+		item.Priority = 100
+	}
+	if s.Config.Display.Priorities == nil {
+		s.Config.Display.Priorities = map[string]int{}
+	}
+	s.Config.Display.Priorities[item.Key] = item.Priority
 }
 
 // Render returns the sections view string
@@ -102,12 +149,25 @@ func (s *SectionsView) Render() string {
 		Foreground(lipgloss.Color("#6B7280")).
 		Italic(true)
 
-	b.WriteString(titleStyle.Render("Toggle Sections"))
+	b.WriteString(titleStyle.Render("Toggle & Order Sections"))
 	b.WriteString("\n\n")
+	if len(s.Config.Display.Lines) > 0 {
+		b.WriteString(descStyle.Render("  Rows and their order come from display.lines; toggles still apply"))
+		b.WriteString("\n\n")
+	}
 
-	for i, item := range s.Items {
+	overrides := s.Project.Overrides()
+	if overrideMark(overrides, "section_order") != "" {
+		b.WriteString(descStyle.Render("  This project sets its own section order") + "\n\n")
+	}
+	start, end := visibleRange(s.Selected, len(s.Items))
+	if start > 0 {
+		b.WriteString(descStyle.Render("  ↑ more") + "\n")
+	}
+	for i := start; i < end; i++ {
+		item := s.Items[i]
 		var checkbox string
-		if *item.Enabled {
+		if item.Enabled {
 			checkbox = checkStyle.Render("[x]")
 		} else {
 			checkbox = uncheckStyle.Render("[ ]")
@@ -120,17 +180,24 @@ func (s *SectionsView) Render() string {
 			label = normalStyle.Render(item.Label)
 		}
 
-		b.WriteString("  " + checkbox + " " + label)
+		mark := overrideMark(overrides, "enabled_sections."+item.Key)
+		if mark == "" {
+			mark = overrideMark(overrides, "display.priorities."+item.Key)
+		}
+		b.WriteString("  " + checkbox + " " + label + " " + uncheckStyle.Render(fmt.Sprintf("p%d", item.Priority)) + mark)
 		if i == s.Selected {
 			b.WriteString("\n")
 			b.WriteString(descStyle.Render("      " + item.Description))
 		}
 		b.WriteString("\n")
 	}
+	if end < len(s.Items) {
+		b.WriteString(descStyle.Render("  ↓ more") + "\n")
+	}
 
 	b.WriteString("\n")
 	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render(
-		"  [space/x] Toggle  [esc] Back"))
+		"  [space/x] Toggle  [shift+↑↓] Reorder  [+/-] Priority  [esc] Back"))
 
 	return b.String()
 }
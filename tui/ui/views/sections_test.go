@@ -0,0 +1,86 @@
+package views
+
+import (
+	"reflect"
+	"testing"
+
+	"statusline-config/config"
+	"statusline-config/render"
+)
+
+// selectSection selects the item with the given key
+func selectSection(t *testing.T, s *SectionsView, key string) {
+	t.Helper()
+	for i, item := range s.Items {
+		if item.Key == key {
+			s.Selected = i
+			return
+		}
+	}
+	t.Fatalf("no section %s", key)
+}
+
+func TestSectionsToggle(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnabledSections = nil
+	s := NewSectionsView(cfg)
+	if len(s.Items) != len(render.Registered()) {
+		t.Fatalf("%d items, want one per registered segment", len(s.Items))
+	}
+
+	// Without a flag each section shows its registered default
+	selectSection(t, s, "cost")
+	if s.Items[s.Selected].Enabled {
+		t.Error("cost is listed as enabled, but is off by default")
+	}
+	s.Toggle()
+	if !cfg.EnabledSections["cost"] || !s.Items[s.Selected].Enabled {
+		t.Errorf("toggling cost on: flags %v", cfg.EnabledSections)
+	}
+	s.Toggle()
+	if v, ok := cfg.EnabledSections["cost"]; !ok || v {
+		t.Errorf("toggling cost off again: flags %v, want cost stored as false", cfg.EnabledSections)
+	}
+}
+
+func TestSectionsMove(t *testing.T) {
+	cfg := config.DefaultConfig()
+	s := NewSectionsView(cfg)
+	first, second := s.Items[0].Key, s.Items[1].Key
+
+	s.Selected = 1
+	s.MoveUp()
+	if s.Selected != 0 || !reflect.DeepEqual(cfg.SectionOrder[:2], []string{second, first}) {
+		t.Errorf("after moving %s up: selected %d, order %v", second, s.Selected, cfg.SectionOrder)
+	}
+	s.MoveUp()
+	if s.Selected != 0 || cfg.SectionOrder[0] != second {
+		t.Errorf("moving the first section up changed the order to %v", cfg.SectionOrder)
+	}
+	if len(cfg.SectionOrder) != len(s.Items) {
+		t.Errorf("order lists %d sections, want all %d", len(cfg.SectionOrder), len(s.Items))
+	}
+
+	// The new order survives rebuilding the view
+	if got := NewSectionsView(cfg).Items[0].Key; got != second {
+		t.Errorf("rebuilt view starts with %s, want %s", got, second)
+	}
+}
+
+func TestSectionsAdjustPriority(t *testing.T) {
+	cfg := config.DefaultConfig()
+	s := NewSectionsView(cfg)
+	selectSection(t, s, "model")
+	start := s.Items[s.Selected].Priority
+
+	s.AdjustPriority(5)
+	if got := cfg.Display.Priorities["model"]; got != min(start+5, 100) {
+		t.Errorf("priority %d after raising %d by 5", got, start)
+	}
+	for i := 0; i < 30; i++ {
+		s.AdjustPriority(-5)
+	}
+	if got := cfg.Display.Priorities["model"]; got != 0 {
+		t.Errorf("priority %d after lowering past 0, want 0", got)
+	}
+}